@@ -0,0 +1,50 @@
+// Package clock abstracts the current time so stored artifacts (response
+// file names, index timestamps, log entries) can be made reproducible.
+package clock
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Clock reports the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the system clock
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+// Fixed is a clock frozen at one instant, for tests and reproducible runs
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time { return time.Time(f) }
+
+// Or returns c, or the system clock if c is nil
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
+
+// SourceDateEpoch is the environment variable, following the reproducible
+// builds convention, that pins the clock to a Unix timestamp
+const SourceDateEpoch = "SOURCE_DATE_EPOCH"
+
+// FromEnv returns a clock fixed at $SOURCE_DATE_EPOCH, or nil if it is unset
+func FromEnv() (Clock, error) {
+	value := os.Getenv(SourceDateEpoch)
+	if value == "" {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a Unix timestamp: %w", SourceDateEpoch, err)
+	}
+	return Fixed(time.Unix(seconds, 0).UTC()), nil
+}
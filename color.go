@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used for diff output
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+)
+
+// colorEnabled reports whether CLI output should be colorized: stdout must be
+// a terminal, and neither --no-color nor the NO_COLOR environment variable
+// (https://no-color.org) may be set
+func colorEnabled(noColor bool) bool {
+	if noColor {
+		return false
+	}
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a character device such as a TTY
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorizeDiff colors the lines of a unified diff: additions green, removals
+// red and hunk headers cyan. File headers are left plain.
+func colorizeDiff(textDiff string) string {
+	lines := strings.Split(textDiff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = ansiCyan + line + ansiReset
+		case strings.HasPrefix(line, "+"):
+			lines[i] = ansiGreen + line + ansiReset
+		case strings.HasPrefix(line, "-"):
+			lines[i] = ansiRed + line + ansiReset
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,207 @@
+package comparator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RootPath addresses the whole document, e.g. a top-level array in ArrayKeys
+const RootPath = "$"
+
+// matchKeyedArrays aligns the arrays configured in arrayKeys (array path ->
+// key field) so elements of modified are in the same order as their
+// counterparts in original. It returns the realigned modified document, a
+// summary entry for every element added, removed or changed, and the
+// configured paths that were aligned. Arrays with an element lacking a unique
+// scalar key are left untouched, falling back to index comparison.
+func matchKeyedArrays(original, modified interface{}, arrayKeys map[string]string) (interface{}, []string, map[string]bool) {
+	paths := make([]string, 0, len(arrayKeys))
+	for path := range arrayKeys {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var changes []string
+	aligned := make(map[string]bool)
+	for _, path := range paths {
+		var segments []pathSegment
+		if path != RootPath {
+			var err error
+			if segments, err = parsePath(path); err != nil {
+				continue
+			}
+		}
+		noun := elementNoun(segments)
+		key := arrayKeys[path]
+		modified = walkPair(original, modified, segments, func(a, b []interface{}) []interface{} {
+			realigned, keyed, ok := alignByKey(a, b, key, noun)
+			if !ok {
+				return b
+			}
+			aligned[normalizeIndexes(path)] = true
+			changes = append(changes, keyed...)
+			return realigned
+		})
+	}
+	return modified, changes, aligned
+}
+
+// anyIndex matches concrete array indexes in a path
+var anyIndex = regexp.MustCompile(`\[\d+\]`)
+
+// normalizeIndexes replaces every "[N]" in a path with "[]"
+func normalizeIndexes(path string) string {
+	return anyIndex.ReplaceAllString(path, "[]")
+}
+
+// walkPair follows segments through both documents in parallel and calls fn
+// with every pair of arrays found at the end. fn returns the replacement for
+// the modified array. "[]" visits every index present in both documents.
+func walkPair(a, b interface{}, segments []pathSegment, fn func(a, b []interface{}) []interface{}) interface{} {
+	if len(segments) == 0 {
+		arrA, okA := a.([]interface{})
+		arrB, okB := b.([]interface{})
+		if !okA || !okB {
+			return b
+		}
+		return fn(arrA, arrB)
+	}
+	seg, rest := segments[0], segments[1:]
+
+	if !seg.IsIndex {
+		mA, okA := a.(map[string]interface{})
+		mB, okB := b.(map[string]interface{})
+		if !okA || !okB {
+			return b
+		}
+		childA, okA := mA[seg.Key]
+		childB, okB := mB[seg.Key]
+		if okA && okB {
+			mB[seg.Key] = walkPair(childA, childB, rest, fn)
+		}
+		return mB
+	}
+
+	arrA, okA := a.([]interface{})
+	arrB, okB := b.([]interface{})
+	if !okA || !okB {
+		return b
+	}
+	for i := range arrB {
+		if i >= len(arrA) || (!seg.AnyIndex && i != seg.Index) {
+			continue
+		}
+		arrB[i] = walkPair(arrA[i], arrB[i], rest, fn)
+	}
+	return arrB
+}
+
+// alignByKey matches elements of a and b by their key field. It returns b
+// reordered to follow a (new elements last) and the keyed change entries.
+// ok is false if any element lacks a unique scalar key.
+func alignByKey(a, b []interface{}, key, noun string) ([]interface{}, []string, bool) {
+	keysA, ok := elementKeys(a, key)
+	if !ok {
+		return nil, nil, false
+	}
+	keysB, ok := elementKeys(b, key)
+	if !ok {
+		return nil, nil, false
+	}
+
+	indexB := make(map[string]int, len(keysB))
+	for i, k := range keysB {
+		indexB[k] = i
+	}
+	indexA := make(map[string]bool, len(keysA))
+
+	var changes []string
+	aligned := make([]interface{}, 0, len(b))
+	for i, k := range keysA {
+		indexA[k] = true
+		j, found := indexB[k]
+		if !found {
+			changes = append(changes, fmt.Sprintf("%s %s=%s removed", noun, key, k))
+			continue
+		}
+		aligned = append(aligned, b[j])
+		for _, field := range changedFields(a[i], b[j]) {
+			changes = append(changes, fmt.Sprintf("%s %s=%s changed field '%s'", noun, key, k, field))
+		}
+	}
+	for j, k := range keysB {
+		if !indexA[k] {
+			aligned = append(aligned, b[j])
+			changes = append(changes, fmt.Sprintf("%s %s=%s added", noun, key, k))
+		}
+	}
+	return aligned, changes, true
+}
+
+// elementKeys returns the formatted key value of every element, failing if an
+// element is not an object, lacks the key, has a non-scalar key or a duplicate
+func elementKeys(arr []interface{}, key string) ([]string, bool) {
+	keys := make([]string, len(arr))
+	seen := make(map[string]bool, len(arr))
+	for i, elem := range arr {
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		value, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}, nil:
+			return nil, false
+		}
+		k := formatValue(value)
+		if seen[k] {
+			return nil, false
+		}
+		seen[k] = true
+		keys[i] = k
+	}
+	return keys, true
+}
+
+// changedFields returns the sorted top-level fields that differ between two objects
+func changedFields(a, b interface{}) []string {
+	mA, _ := a.(map[string]interface{})
+	mB, _ := b.(map[string]interface{})
+	var fields []string
+	for k, va := range mA {
+		if vb, ok := mB[k]; !ok || !deepEqual(va, vb) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range mB {
+		if _, ok := mA[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// elementNoun derives a name for array elements from the array's key, e.g.
+// "data.users" -> "user", "categories" -> "category"
+func elementNoun(segments []pathSegment) string {
+	for i := len(segments) - 1; i >= 0; i-- {
+		name := segments[i].Key
+		if name == "" {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, "ies") && len(name) > 3:
+			return name[:len(name)-3] + "y"
+		case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+			return name[:len(name)-1]
+		}
+		return name
+	}
+	return "element"
+}
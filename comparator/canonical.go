@@ -0,0 +1,59 @@
+package comparator
+
+import "sort"
+
+// sortScalarArrays sorts, in place, every array whose elements are all
+// scalars (strings, numbers, booleans, null). Arrays containing objects or
+// arrays keep their order, but their elements are still visited.
+func sortScalarArrays(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = sortScalarArrays(child)
+		}
+	case []interface{}:
+		scalars := true
+		for i, child := range val {
+			switch child.(type) {
+			case map[string]interface{}, []interface{}:
+				scalars = false
+				val[i] = sortScalarArrays(child)
+			}
+		}
+		if scalars {
+			sort.SliceStable(val, func(i, j int) bool { return scalarLess(val[i], val[j]) })
+		}
+	}
+	return v
+}
+
+// scalarRank orders scalar types: null, booleans, numbers, strings
+func scalarRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// scalarLess orders scalars by type, then by value
+func scalarLess(a, b interface{}) bool {
+	ra, rb := scalarRank(a), scalarRank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	switch x := a.(type) {
+	case bool:
+		return !x && b.(bool)
+	case float64:
+		return x < b.(float64)
+	case string:
+		return x < b.(string)
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package comparator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CardinalityDiff compares the distinct values found at a path in two
+// responses, ignoring element order and duplicates
+type CardinalityDiff struct {
+	Path     string   `json:"path"`
+	Distinct [2]int   `json:"distinct"`          // Distinct value count in original and modified
+	Added    []string `json:"added,omitempty"`   // JSON-encoded values only present in modified
+	Removed  []string `json:"removed,omitempty"` // JSON-encoded values only present in original
+}
+
+// HasChanges reports whether the sets of distinct values differ
+func (c CardinalityDiff) HasChanges() bool {
+	return len(c.Added) > 0 || len(c.Removed) > 0
+}
+
+// Summary describes the cardinality change in one line
+func (c CardinalityDiff) Summary() string {
+	s := fmt.Sprintf("Cardinality '%s': %d → %d distinct values", c.Path, c.Distinct[0], c.Distinct[1])
+	if len(c.Added) > 0 {
+		s += fmt.Sprintf(", added: %s", strings.Join(c.Added, ", "))
+	}
+	if len(c.Removed) > 0 {
+		s += fmt.Sprintf(", removed: %s", strings.Join(c.Removed, ", "))
+	}
+	return s
+}
+
+// compareCardinality diffs the distinct values at each path. Paths use the
+// same syntax as LabelPath, where "[]" fans out over every array element,
+// e.g. "items[].category".
+func compareCardinality(v1, v2 interface{}, paths []string) []CardinalityDiff {
+	var diffs []CardinalityDiff
+	for _, path := range paths {
+		segments, err := parsePath(path)
+		if err != nil {
+			continue
+		}
+		set1 := distinctValues(v1, segments)
+		set2 := distinctValues(v2, segments)
+
+		diff := CardinalityDiff{Path: path, Distinct: [2]int{len(set1), len(set2)}}
+		for value := range set2 {
+			if !set1[value] {
+				diff.Added = append(diff.Added, value)
+			}
+		}
+		for value := range set1 {
+			if !set2[value] {
+				diff.Removed = append(diff.Removed, value)
+			}
+		}
+		sort.Strings(diff.Added)
+		sort.Strings(diff.Removed)
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// distinctValues collects the set of values reachable through segments,
+// keyed by their JSON encoding so that "1" and 1 stay distinct
+func distinctValues(v interface{}, segments []pathSegment) map[string]bool {
+	set := make(map[string]bool)
+	collectValues(v, segments, func(value interface{}) {
+		b, err := json.Marshal(value)
+		if err == nil {
+			set[string(b)] = true
+		}
+	})
+	return set
+}
+
+// collectValues walks v along segments, visiting every element for "[]"
+func collectValues(v interface{}, segments []pathSegment, visit func(interface{})) {
+	if len(segments) == 0 {
+		visit(v)
+		return
+	}
+	seg, rest := segments[0], segments[1:]
+
+	if seg.IsIndex {
+		arr, ok := v.([]interface{})
+		if !ok {
+			return
+		}
+		if seg.AnyIndex {
+			for _, item := range arr {
+				collectValues(item, rest, visit)
+			}
+		} else if seg.Index < len(arr) {
+			collectValues(arr[seg.Index], rest, visit)
+		}
+		return
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if child, ok := m[seg.Key]; ok {
+		collectValues(child, rest, visit)
+	}
+}
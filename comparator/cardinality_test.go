@@ -0,0 +1,26 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCardinalityKeepsTypesDistinct(t *testing.T) {
+	original := []byte(`{"items": [{"v": 1}, {"v": true}, {"v": "x"}]}`)
+	modified := []byte(`{"items": [{"v": "1"}, {"v": "true"}, {"v": "x"}]}`)
+
+	result, err := CompareWithOptions(original, modified, "a", "b", CompareOptions{CardinalityPaths: []string{"items[].v"}})
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if len(result.Cardinality) != 1 {
+		t.Fatalf("Cardinality = %+v, want one diff", result.Cardinality)
+	}
+	diff := result.Cardinality[0]
+	if got := strings.Join(diff.Added, ","); got != `"1","true"` {
+		t.Errorf("Added = %s, want the string values", got)
+	}
+	if got := strings.Join(diff.Removed, ","); got != `1,true` {
+		t.Errorf("Removed = %s, want the number and boolean", got)
+	}
+}
@@ -0,0 +1,129 @@
+package comparator
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Change kinds
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+
+	// A key kept on both sides whose value became null, or stopped being
+	// null. Reported apart from removals since explicit nulls are part of
+	// many contracts (e.g. PATCH semantics); counted as changes in ChangeSet.
+	ChangeNulled   = "set to null"
+	ChangeUnnulled = "un-nulled"
+)
+
+// changeKind classifies a value change present on both sides
+func changeKind(old, new interface{}) string {
+	switch {
+	case new == nil && old != nil:
+		return ChangeNulled
+	case old == nil && new != nil:
+		return ChangeUnnulled
+	}
+	return ChangeChanged
+}
+
+// leafChange is a single difference between two JSON documents at a leaf path
+type leafChange struct {
+	Path string // Full path, e.g. "data.items[3].price"
+	Kind string // ChangeAdded, ChangeRemoved, ChangeChanged, ChangeNulled or ChangeUnnulled
+	Old  interface{}
+	New  interface{}
+}
+
+// ChangeSet lists the full leaf paths (e.g. "data.items[3].price") that were
+// added, removed or changed, for tooling that aggregates differences
+type ChangeSet struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// newChangeSet groups sorted leaf changes by kind
+func newChangeSet(changes []leafChange) *ChangeSet {
+	set := &ChangeSet{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for _, c := range changes {
+		switch c.Kind {
+		case ChangeAdded:
+			set.Added = append(set.Added, c.Path)
+		case ChangeRemoved:
+			set.Removed = append(set.Removed, c.Path)
+		default:
+			set.Changed = append(set.Changed, c.Path)
+		}
+	}
+	return set
+}
+
+// joinPath appends an object key to a path
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// indexPath appends an array index to a path
+func indexPath(prefix string, i int) string {
+	return prefix + "[" + strconv.Itoa(i) + "]"
+}
+
+// flattenLeaves records every leaf value of v under its full path. Scalars,
+// empty objects and empty arrays are leaves.
+func flattenLeaves(v interface{}, prefix string, out map[string]interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if len(val) == 0 {
+			out[prefix] = val
+			return
+		}
+		for k, child := range val {
+			flattenLeaves(child, joinPath(prefix, k), out)
+		}
+	case []interface{}:
+		if len(val) == 0 {
+			out[prefix] = val
+			return
+		}
+		for i, child := range val {
+			flattenLeaves(child, indexPath(prefix, i), out)
+		}
+	default:
+		out[prefix] = val
+	}
+}
+
+// diffLeaves compares two documents leaf by leaf. It returns the changes
+// sorted by path and the total number of distinct leaf paths in both documents.
+func diffLeaves(v1, v2 interface{}) ([]leafChange, int) {
+	leaves1 := make(map[string]interface{})
+	leaves2 := make(map[string]interface{})
+	flattenLeaves(v1, "", leaves1)
+	flattenLeaves(v2, "", leaves2)
+
+	var changes []leafChange
+	total := len(leaves1)
+	for path, old := range leaves1 {
+		newVal, ok := leaves2[path]
+		if !ok {
+			changes = append(changes, leafChange{Path: path, Kind: ChangeRemoved, Old: old})
+		} else if !deepEqual(old, newVal) {
+			changes = append(changes, leafChange{Path: path, Kind: changeKind(old, newVal), Old: old, New: newVal})
+		}
+	}
+	for path, newVal := range leaves2 {
+		if _, ok := leaves1[path]; !ok {
+			total++
+			changes = append(changes, leafChange{Path: path, Kind: ChangeAdded, New: newVal})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, total
+}
@@ -0,0 +1,47 @@
+package comparator
+
+import "testing"
+
+func TestChangeKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new interface{}
+		want     string
+	}{
+		{"value to null", "x", nil, ChangeNulled},
+		{"null to value", nil, 1.0, ChangeUnnulled},
+		{"value to value", "x", "y", ChangeChanged},
+	}
+	for _, tt := range tests {
+		if got := changeKind(tt.old, tt.new); got != tt.want {
+			t.Errorf("%s: changeKind(%v, %v) = %q, want %q", tt.name, tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestCollectChangesNullTransitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		v1, v2 map[string]interface{}
+		want   string
+	}{
+		{"absent to null", map[string]interface{}{}, map[string]interface{}{"a": nil}, ChangeAdded},
+		{"null to absent", map[string]interface{}{"a": nil}, map[string]interface{}{}, ChangeRemoved},
+		{"null to value", map[string]interface{}{"a": nil}, map[string]interface{}{"a": "x"}, ChangeUnnulled},
+		{"value to null", map[string]interface{}{"a": "x"}, map[string]interface{}{"a": nil}, ChangeNulled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kinds []string
+			collectChanges(tt.v1, tt.v2, "", 0, nil, func(path, kind string, old, new interface{}) {
+				if path != "a" {
+					t.Errorf("reported path %q, want %q", path, "a")
+				}
+				kinds = append(kinds, kind)
+			})
+			if len(kinds) != 1 || kinds[0] != tt.want {
+				t.Errorf("reported %v, want [%s]", kinds, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package comparator
+
+// Comparer compares two response bodies. The engine runs every version pair
+// through one, so a custom implementation can handle formats the built-in
+// comparison doesn't understand.
+type Comparer interface {
+	Compare(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error)
+}
+
+// ComparerFunc adapts a function to the Comparer interface
+type ComparerFunc func(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error)
+
+func (f ComparerFunc) Compare(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error) {
+	return f(original, modified, name1, name2, opts)
+}
+
+// DefaultComparer is the built-in comparison (CompareWithOptions)
+var DefaultComparer Comparer = ComparerFunc(CompareWithOptions)
@@ -0,0 +1,33 @@
+package comparator
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// MediaType returns the lowercased media type of a Content-Type header,
+// without parameters such as charset ("" if contentType is empty)
+func MediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// compareContentTypes returns a result describing a content-type mismatch,
+// e.g. "content-type mismatch: application/json vs text/html", or nil if
+// either type is unknown or both have the same media type. A line diff of
+// a JSON body against an HTML error page says nothing the mismatch doesn't.
+func compareContentTypes(original, modified string) *DiffResult {
+	type1, type2 := MediaType(original), MediaType(modified)
+	if type1 == "" || type2 == "" || type1 == type2 {
+		return nil
+	}
+	return &DiffResult{
+		JsonPatch:           []byte("[]"),
+		Summary:             fmt.Sprintf("content-type mismatch: %s vs %s", type1, type2),
+		ContentTypeMismatch: true,
+	}
+}
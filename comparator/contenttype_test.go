@@ -0,0 +1,39 @@
+package comparator
+
+import "testing"
+
+func TestContentTypeMismatch(t *testing.T) {
+	original := []byte(`{"id": 1}`)
+	modified := []byte(`<html><body>Bad Gateway</body></html>`)
+	opts := CompareOptions{
+		OriginalContentType: "application/json; charset=utf-8",
+		ModifiedContentType: "text/html",
+	}
+
+	result, err := CompareWithOptions(original, modified, "v1", "v2", opts)
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if !result.ContentTypeMismatch || result.Summary != "content-type mismatch: application/json vs text/html" {
+		t.Errorf("ContentTypeMismatch = %v, Summary = %q", result.ContentTypeMismatch, result.Summary)
+	}
+	if result.TextDiff != "" {
+		t.Errorf("TextDiff = %q, want none", result.TextDiff)
+	}
+}
+
+func TestContentTypeParametersIgnored(t *testing.T) {
+	body := []byte(`{"id": 1}`)
+	opts := CompareOptions{
+		OriginalContentType: "application/json; charset=utf-8",
+		ModifiedContentType: "Application/JSON",
+	}
+
+	result, err := CompareWithOptions(body, body, "v1", "v2", opts)
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if result.ContentTypeMismatch || result.Summary != NoChangesSummary {
+		t.Errorf("ContentTypeMismatch = %v, Summary = %q; want no changes", result.ContentTypeMismatch, result.Summary)
+	}
+}
@@ -1,8 +1,11 @@
 package comparator
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -15,11 +18,124 @@ type DiffResult struct {
 	JsonPatch []byte `json:"json_patch"`
 	Summary   string `json:"summary"`
 	IsJSON    bool   `json:"is_json"` // Indicates if both inputs were valid JSON
+
+	// ChangedFields and TotalFields count leaf fields (JSON only), giving a
+	// measure of how much of the response changed
+	ChangedFields int `json:"changed_fields"`
+	TotalFields   int `json:"total_fields"`
+
+	// Cardinality compares distinct values per configured path (see CompareOptions.CardinalityPaths)
+	Cardinality []CardinalityDiff `json:"cardinality,omitempty"`
+
+	// Sections groups leaf changes by top-level key (only with CompareOptions.GroupBySection)
+	Sections []SectionChanges `json:"sections,omitempty"`
+
+	// Changes lists the leaf paths that differ, for tooling (JSON only;
+	// not set for streamed comparisons)
+	Changes *ChangeSet `json:"changes,omitempty"`
+
+	// ContentTypeMismatch is set when the responses have different media
+	// types, in which case there is no text diff
+	ContentTypeMismatch bool `json:"content_type_mismatch,omitempty"`
+
+	// OriginalEmpty and ModifiedEmpty mark empty or whitespace-only
+	// responses (see CompareEmpty)
+	OriginalEmpty bool `json:"original_empty,omitempty"`
+	ModifiedEmpty bool `json:"modified_empty,omitempty"`
+}
+
+// ChangedPercent returns the percentage of leaf fields that changed. Non-JSON
+// content counts as 100% changed unless it is identical.
+func (d *DiffResult) ChangedPercent() float64 {
+	if d.ContentTypeMismatch || d.OriginalEmpty != d.ModifiedEmpty {
+		return 100
+	}
+	if !d.IsJSON {
+		if d.TextDiff == "" {
+			return 0
+		}
+		return 100
+	}
+	if d.TotalFields == 0 {
+		return 0
+	}
+	return float64(d.ChangedFields) * 100 / float64(d.TotalFields)
 }
 
+// NoChangesSummary is the summary reported when no differences are found
+const NoChangesSummary = "No top-level changes"
+
 // CompareOptions allows customization of comparison behavior
 type CompareOptions struct {
 	KeysOnly bool // If true, only compare JSON structure (keys), not values
+
+	// LabelPath is a path (e.g. "order.id") whose value is extracted from the
+	// response and prefixed to each change in the summary for context
+	LabelPath string
+
+	// StreamThreshold switches to element-by-element streaming comparison
+	// when both inputs are top-level arrays and either is larger than this
+	// many bytes (0 = never stream). Ignored with KeysOnly, LabelPath,
+	// CardinalityPaths, IgnorePaths, ArrayKeys, MaskRules or SortArrays.
+	StreamThreshold int
+
+	// CardinalityPaths lists paths (e.g. "items[].category") whose sets of
+	// distinct values are compared instead of element by element
+	CardinalityPaths []string
+
+	// IgnorePaths lists paths (e.g. "data.requestId", "items[].createdAt")
+	// or globs (e.g. "**.createdAt", "meta.*") removed from both documents
+	// before comparing. Missing paths are ignored.
+	IgnorePaths []string
+
+	// GroupBySection additionally counts changes per top-level key
+	// (e.g. "3 changes in data, 1 in meta") in DiffResult.Sections
+	GroupBySection bool
+
+	// ArrayKeys maps an array path (e.g. "data.users", or "$" for a top-level
+	// array) to an identifier field (e.g. "id"). Elements are matched by that
+	// field instead of position, so reordering is not reported as a change.
+	ArrayKeys map[string]string
+
+	// MaskRules replace matching parts of string values with a canonical
+	// token on both sides before comparison, so random content is ignored
+	// while a change of shape (e.g. a UUID becoming null) is still reported
+	MaskRules []MaskRule
+
+	// Canonicalize re-serializes both documents with sorted object keys
+	// before diffing, so the text diff doesn't depend on the servers' key
+	// order. SortArrays additionally sorts arrays of scalars (implies
+	// Canonicalize); the summary and JSON patch see the sorted values too.
+	Canonicalize bool
+	SortArrays   bool
+
+	// OriginalContentType and ModifiedContentType are the responses'
+	// Content-Type headers, when known. If both are set, their media types
+	// differ and the bodies aren't both JSON, the result reports the
+	// mismatch instead of a line diff.
+	OriginalContentType string
+	ModifiedContentType string
+
+	// ContextLines is how many unchanged lines the text diff shows around
+	// each change (nil = DefaultContextLines; 0 shows only changed lines)
+	ContextLines *int
+
+	// StripPrefixes lists guards (e.g. XSSIPrefix) removed from the start of
+	// a response before it is parsed as JSON. A UTF-8 byte order mark is
+	// always removed. The text diff still shows the bodies as received.
+	StripPrefixes []string
+}
+
+// DefaultContextLines is the number of context lines in text diffs when
+// CompareOptions.ContextLines is not set
+const DefaultContextLines = 3
+
+// contextLines returns the configured number of context lines
+func (opts CompareOptions) contextLines() int {
+	if opts.ContextLines == nil || *opts.ContextLines < 0 {
+		return DefaultContextLines
+	}
+	return *opts.ContextLines
 }
 
 // isValidJSON checks if the byte slice is valid JSON
@@ -35,13 +151,32 @@ func Compare(original, modified []byte, name1, name2 string) (*DiffResult, error
 
 // CompareWithOptions compares with configurable options
 func CompareWithOptions(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error) {
+	// A byte order mark or XSSI guard doesn't make a response not JSON
+	clean1 := stripJSONPrefix(original, opts.StripPrefixes)
+	clean2 := stripJSONPrefix(modified, opts.StripPrefixes)
+
+	// Large arrays are compared without decoding either document in full.
+	// Malformed input falls through to the regular path below.
+	if shouldStream(clean1, clean2, opts) {
+		if result, err := compareArraysStreaming(bytes.NewReader(clean1), bytes.NewReader(clean2), name1, name2, opts.contextLines()); err == nil {
+			return result, nil
+		}
+	}
+
 	// Check if both are valid JSON
-	isJSON1 := isValidJSON(original)
-	isJSON2 := isValidJSON(modified)
+	isJSON1 := isValidJSON(clean1)
+	isJSON2 := isValidJSON(clean2)
 
-	// If either is not JSON, do a plain text comparison
+	// If either is not JSON, compare newline-delimited JSON record by record,
+	// and anything else as plain text
 	if !isJSON1 || !isJSON2 {
-		return compareAsText(original, modified, name1, name2, isJSON1, isJSON2)
+		if result := compareContentTypes(opts.OriginalContentType, opts.ModifiedContentType); result != nil {
+			return result, nil
+		}
+		if isNDJSON(original, modified) {
+			return compareAsNDJSON(original, modified, name1, name2, opts)
+		}
+		return compareAsText(original, modified, name1, name2, isJSON1, isJSON2, opts.contextLines())
 	}
 
 	// Both are JSON, proceed with JSON comparison
@@ -49,14 +184,14 @@ func CompareWithOptions(original, modified []byte, name1, name2 string, opts Com
 }
 
 // compareAsText performs a plain text diff when content is not JSON
-func compareAsText(original, modified []byte, name1, name2 string, isJSON1, isJSON2 bool) (*DiffResult, error) {
+func compareAsText(original, modified []byte, name1, name2 string, isJSON1, isJSON2 bool, context int) (*DiffResult, error) {
 	// Create unified diff
 	diff := difflib.UnifiedDiff{
 		A:        difflib.SplitLines(string(original)),
 		B:        difflib.SplitLines(string(modified)),
 		FromFile: name1,
 		ToFile:   name2,
-		Context:  3,
+		Context:  context,
 	}
 	textDiff, err := difflib.GetUnifiedDiffString(diff)
 	if err != nil {
@@ -87,33 +222,106 @@ func compareAsText(original, modified []byte, name1, name2 string, isJSON1, isJS
 	}, nil
 }
 
-// compareAsJSON performs a JSON-aware comparison
-func compareAsJSON(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error) {
+// preparedJSON holds two decoded documents after every CompareOptions
+// transform, with the serialized forms the text diff is built from
+type preparedJSON struct {
+	v1, v2             interface{}
+	original, modified []byte
+	label              string
+	cardinality        []CardinalityDiff
+	keyedChanges       []string
+	keyedPaths         map[string]bool
+}
+
+// prepareJSON decodes both documents (without their StripPrefixes) and
+// applies masking, ignored paths, keyed array alignment, canonicalization and
+// keys-only extraction
+func prepareJSON(original, modified []byte, opts CompareOptions) (*preparedJSON, error) {
 	var v1, v2 interface{}
-	if err := json.Unmarshal(original, &v1); err != nil {
+	if err := json.Unmarshal(stripJSONPrefix(original, opts.StripPrefixes), &v1); err != nil {
 		return nil, fmt.Errorf("invalid json in original: %w", err)
 	}
-	if err := json.Unmarshal(modified, &v2); err != nil {
+	if err := json.Unmarshal(stripJSONPrefix(modified, opts.StripPrefixes), &v2); err != nil {
 		return nil, fmt.Errorf("invalid json in modified: %w", err)
 	}
 
+	// Normalize masked values, re-marshalling so the text diff shows the tokens
+	if len(opts.MaskRules) > 0 {
+		v1 = applyMasks(v1, opts.MaskRules)
+		v2 = applyMasks(v2, opts.MaskRules)
+		original = indentJSON(v1)
+		modified = indentJSON(v2)
+	}
+
+	// Extract the label before keys-only mode replaces values with type markers
+	label := extractLabel(v1, v2, opts.LabelPath)
+
+	// Drop volatile fields, re-marshalling so the text diff omits them too
+	if len(opts.IgnorePaths) > 0 {
+		v1 = pruneIgnored(v1, opts.IgnorePaths)
+		v2 = pruneIgnored(v2, opts.IgnorePaths)
+		original = indentJSON(v1)
+		modified = indentJSON(v2)
+	}
+
+	cardinality := compareCardinality(v1, v2, opts.CardinalityPaths)
+
+	// Match keyed array elements by identifier, realigning v2 to v1's order
+	var keyedChanges []string
+	var keyedPaths map[string]bool
+	if len(opts.ArrayKeys) > 0 {
+		v2, keyedChanges, keyedPaths = matchKeyedArrays(v1, v2, opts.ArrayKeys)
+		if !opts.KeysOnly {
+			original = indentJSON(v1)
+			modified = indentJSON(v2)
+		}
+	}
+
+	// Canonical form: encoding/json writes map keys in sorted order
+	if opts.SortArrays {
+		v1 = sortScalarArrays(v1)
+		v2 = sortScalarArrays(v2)
+	}
+	if opts.Canonicalize || opts.SortArrays {
+		original = indentJSON(v1)
+		modified = indentJSON(v2)
+	}
+
 	// If keys-only mode, extract and compare only the structure
 	if opts.KeysOnly {
 		v1 = extractKeys(v1)
 		v2 = extractKeys(v2)
 
-		// Re-marshal for text diff
-		original, _ = json.MarshalIndent(v1, "", "  ")
-		modified, _ = json.MarshalIndent(v2, "", "  ")
+		// Re-marshal for text diff (keys sorted, so output is deterministic)
+		original = indentJSON(v1)
+		modified = indentJSON(v2)
+	}
+
+	return &preparedJSON{
+		v1: v1, v2: v2,
+		original: original, modified: modified,
+		label:        label,
+		cardinality:  cardinality,
+		keyedChanges: keyedChanges,
+		keyedPaths:   keyedPaths,
+	}, nil
+}
+
+// compareAsJSON performs a JSON-aware comparison
+func compareAsJSON(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error) {
+	p, err := prepareJSON(original, modified, opts)
+	if err != nil {
+		return nil, err
 	}
+	v1, v2, label := p.v1, p.v2, p.label
 
 	// 1. Unified Diff (Text)
 	diff := difflib.UnifiedDiff{
-		A:        difflib.SplitLines(string(original)),
-		B:        difflib.SplitLines(string(modified)),
+		A:        difflib.SplitLines(string(p.original)),
+		B:        difflib.SplitLines(string(p.modified)),
 		FromFile: name1,
 		ToFile:   name2,
-		Context:  3,
+		Context:  opts.contextLines(),
 	}
 	textDiff, err := difflib.GetUnifiedDiffString(diff)
 	if err != nil {
@@ -125,6 +333,7 @@ func compareAsJSON(original, modified []byte, name1, name2 string, opts CompareO
 	if err != nil {
 		return nil, fmt.Errorf("jsondiff failed: %w", err)
 	}
+	sortPatch(patch)
 
 	patchBytes, err := json.MarshalIndent(patch, "", "  ")
 	if err != nil {
@@ -134,17 +343,35 @@ func compareAsJSON(original, modified []byte, name1, name2 string, opts CompareO
 	// 3. Summary
 	var summary string
 	if opts.KeysOnly {
-		summary = summarizeKeyDifferences(v1, v2)
+		summary = summarizeKeyDifferences(v1, v2, label)
 	} else {
-		summary = summarizeDifferences(v1, v2)
+		summary = summarizeDifferences(v1, v2, label, p.keyedPaths)
+		if len(p.keyedChanges) > 0 {
+			keyed := joinChanges(p.keyedChanges, label)
+			if summary == NoChangesSummary {
+				summary = keyed
+			} else {
+				summary += ", " + keyed
+			}
+		}
 	}
 
-	return &DiffResult{
-		TextDiff:  textDiff,
-		JsonPatch: patchBytes,
-		Summary:   summary,
-		IsJSON:    true,
-	}, nil
+	leafChanges, totalFields := diffLeaves(v1, v2)
+
+	result := &DiffResult{
+		TextDiff:      textDiff,
+		JsonPatch:     patchBytes,
+		Summary:       summary,
+		IsJSON:        true,
+		ChangedFields: len(leafChanges),
+		TotalFields:   totalFields,
+		Cardinality:   p.cardinality,
+		Changes:       newChangeSet(leafChanges),
+	}
+	if opts.GroupBySection {
+		result.Sections = groupBySection(leafChanges)
+	}
+	return result, nil
 }
 
 // extractKeys recursively extracts only the structure (keys) from JSON
@@ -176,7 +403,44 @@ func extractKeys(v interface{}) interface{} {
 	}
 }
 
-func summarizeKeyDifferences(v1, v2 interface{}) string {
+// extractLabel returns "path=value" for the configured label path, preferring
+// the modified response. Returns "" if no label is configured or found.
+func extractLabel(v1, v2 interface{}, labelPath string) string {
+	if labelPath == "" {
+		return ""
+	}
+	for _, v := range []interface{}{v2, v1} {
+		if val, ok := lookupPath(v, labelPath); ok {
+			switch val.(type) {
+			case map[string]interface{}, []interface{}:
+				// Only scalar values make useful labels
+				continue
+			}
+			return fmt.Sprintf("%s=%s", labelPath, formatValue(val))
+		}
+	}
+	return ""
+}
+
+// joinChanges sorts the changes, prefixes them with the label (if any) and
+// joins them into a summary string
+func joinChanges(changes []string, label string) string {
+	if len(changes) == 0 {
+		return NoChangesSummary
+	}
+
+	// Sort for consistent output
+	sort.Strings(changes)
+
+	if label != "" {
+		for i, c := range changes {
+			changes[i] = label + ": " + c
+		}
+	}
+	return strings.Join(changes, ", ")
+}
+
+func summarizeKeyDifferences(v1, v2 interface{}, label string) string {
 	keys1 := collectAllKeys(v1, "")
 	keys2 := collectAllKeys(v2, "")
 
@@ -196,13 +460,7 @@ func summarizeKeyDifferences(v1, v2 interface{}) string {
 		}
 	}
 
-	// Sort for consistent output
-	sort.Strings(changes)
-
-	if len(changes) == 0 {
-		return "No top-level changes"
-	}
-	return strings.Join(changes, ", ")
+	return joinChanges(changes, label)
 }
 
 func collectAllKeys(v interface{}, prefix string) map[string]bool {
@@ -233,84 +491,198 @@ func collectAllKeys(v interface{}, prefix string) map[string]bool {
 	return keys
 }
 
-// summarizeDifferences creates a human-readable summary of changes
-func summarizeDifferences(v1, v2 interface{}) string {
+// maxSummaryDepth bounds how deep summarizeDifferences descends; differences
+// below it are reported at the deepest path reached
+const maxSummaryDepth = 32
+
+// summarizeDifferences creates a human-readable summary of changes, naming the
+// deepest path that differs (e.g. "Field 'a.b.c' changed"). Arrays in keyed
+// (aligned by matchKeyedArrays) are not descended, as their keyed summary
+// entries already describe them.
+func summarizeDifferences(v1, v2 interface{}, label string, keyed map[string]bool) string {
 	// Handle arrays at the top level
 	arr1, isArr1 := v1.([]interface{})
 	arr2, isArr2 := v2.([]interface{})
 
 	if isArr1 && isArr2 {
-		return summarizeArrayDifferences(arr1, arr2)
+		if keyed[RootPath] {
+			return NoChangesSummary
+		}
+		summary := summarizeArrayDifferences(arr1, arr2)
+		if summary == NoChangesSummary {
+			return summary
+		}
+		return joinChanges([]string{summary}, label)
 	}
 
 	// Handle objects at the top level
-	m1, isMap1 := v1.(map[string]interface{})
-	m2, isMap2 := v2.(map[string]interface{})
+	_, isMap1 := v1.(map[string]interface{})
+	_, isMap2 := v2.(map[string]interface{})
 
 	if !isMap1 || !isMap2 {
 		if fmt.Sprintf("%v", v1) == fmt.Sprintf("%v", v2) {
-			return "No top-level changes"
+			return NoChangesSummary
 		}
-		return "Top-level value changed"
+		return joinChanges([]string{"Top-level value changed"}, label)
 	}
 
 	var changes []string
+	collectChanges(v1, v2, "", 0, keyed, func(path, kind string, _, _ interface{}) {
+		changes = append(changes, fmt.Sprintf("Field '%s' %s", path, kind))
+	})
+	return joinChanges(changes, label)
+}
 
-	// Check keys in m1
-	for k, val1 := range m1 {
-		val2, ok := m2[k]
-		if !ok {
-			changes = append(changes, fmt.Sprintf("Field '%s' removed", k))
-			continue
-		}
-		if !deepEqual(val1, val2) {
-			changes = append(changes, fmt.Sprintf("Field '%s' changed", k))
-		}
-	}
-	// Check keys in m2 that are not in m1
-	for k := range m2 {
-		if _, ok := m1[k]; !ok {
-			changes = append(changes, fmt.Sprintf("Field '%s' added", k))
+// collectChanges descends into objects and arrays present on both sides and
+// reports every added, removed or changed path with its kind (ChangeAdded,
+// ChangeRemoved, ChangeChanged, or ChangeNulled/ChangeUnnulled when a kept
+// key's value became or stopped being null)
+func collectChanges(v1, v2 interface{}, path string, depth int, keyed map[string]bool, report func(path, kind string, old, new interface{})) {
+	if depth < maxSummaryDepth {
+		switch a := v1.(type) {
+		case map[string]interface{}:
+			if b, ok := v2.(map[string]interface{}); ok {
+				for k, child := range a {
+					childPath := joinPath(path, k)
+					if other, ok := b[k]; ok {
+						collectChanges(child, other, childPath, depth+1, keyed, report)
+					} else {
+						report(childPath, ChangeRemoved, child, nil)
+					}
+				}
+				for k, child := range b {
+					if _, ok := a[k]; !ok {
+						report(joinPath(path, k), ChangeAdded, nil, child)
+					}
+				}
+				return
+			}
+		case []interface{}:
+			if b, ok := v2.([]interface{}); ok {
+				if keyed[normalizeIndexes(path)] {
+					return
+				}
+				for i := 0; i < len(a) || i < len(b); i++ {
+					switch {
+					case i >= len(b):
+						report(indexPath(path, i), ChangeRemoved, a[i], nil)
+					case i >= len(a):
+						report(indexPath(path, i), ChangeAdded, nil, b[i])
+					default:
+						collectChanges(a[i], b[i], indexPath(path, i), depth+1, keyed, report)
+					}
+				}
+				return
+			}
 		}
 	}
-
-	// Sort for consistent output
-	sort.Strings(changes)
-
-	if len(changes) == 0 {
-		return "No top-level changes"
+	if !deepEqual(v1, v2) {
+		report(path, changeKind(v1, v2), v1, v2)
 	}
-	return strings.Join(changes, ", ")
 }
 
-// summarizeArrayDifferences handles top-level array comparisons
+// summarizeArrayDifferences handles top-level array comparisons, aligning
+// the elements with diffArrays so an insertion at the front doesn't count as
+// every element changing. Arrays matched by key (ArrayKeys "$") never get
+// here; arrays too large to align get a positional summary.
 func summarizeArrayDifferences(arr1, arr2 []interface{}) string {
-	len1, len2 := len(arr1), len(arr2)
-
-	if len1 != len2 {
-		return fmt.Sprintf("Array length changed: %d → %d items", len1, len2)
+	if edit, ok := diffArrays(arr1, arr2); ok {
+		if edit.added+edit.removed+edit.changed+edit.moved == 0 {
+			return NoChangesSummary
+		}
+		return edit.summary()
 	}
+	return positionalArraySummary(arr1, arr2)
+}
 
-	changedCount := 0
-	for i := 0; i < len1; i++ {
+// positionalArraySummary compares arrays index by index
+func positionalArraySummary(arr1, arr2 []interface{}) string {
+	changed := 0
+	for i := 0; i < len(arr1) && i < len(arr2); i++ {
 		if !deepEqual(arr1[i], arr2[i]) {
-			changedCount++
+			changed++
 		}
 	}
+	return positionalSummary(len(arr1), len(arr2), changed)
+}
 
-	if changedCount == 0 {
-		return "No top-level changes"
+// positionalSummary describes arrays compared index by index, given their
+// lengths and how many common indexes differ. It says so, since an insertion
+// shifts every later element and such summaries differ from the aligned ones
+// of smaller arrays.
+func positionalSummary(len1, len2, changed int) string {
+	switch {
+	case len1 != len2:
+		return fmt.Sprintf("Array length changed: %d → %d items (compared by position)", len1, len2)
+	case changed == 0:
+		return NoChangesSummary
+	default:
+		return fmt.Sprintf("Array: %d of %d items changed (compared by position)", changed, len1)
 	}
-
-	return fmt.Sprintf("Array: %d of %d items changed", changedCount, len1)
 }
 
-// deepEqual performs a deep comparison of two values
+// deepEqual reports whether two decoded JSON values are structurally equal.
+// Numbers compare by value whatever their Go type (1 equals 1.0), objects key
+// by key (a key holding null differs from a missing key) and arrays element
+// by element.
 func deepEqual(v1, v2 interface{}) bool {
-	b1, err1 := json.Marshal(v1)
-	b2, err2 := json.Marshal(v2)
-	if err1 != nil || err2 != nil {
-		return false
+	switch a := v1.(type) {
+	case map[string]interface{}:
+		b, ok := v2.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, va := range a {
+			vb, ok := b[k]
+			if !ok || !deepEqual(va, vb) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := v2.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !deepEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return v2 == nil
+	case string:
+		b, ok := v2.(string)
+		return ok && a == b
+	case bool:
+		b, ok := v2.(bool)
+		return ok && a == b
+	}
+
+	if n1, ok := numberValue(v1); ok {
+		n2, ok := numberValue(v2)
+		return ok && n1.Cmp(n2) == 0
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+// numberValue converts any JSON number representation to an exact rational,
+// so large integers decoded as json.Number keep full precision
+func numberValue(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case float64:
+		r := new(big.Rat)
+		if r.SetFloat64(n) == nil {
+			return nil, false
+		}
+		return r, true
+	case json.Number:
+		return new(big.Rat).SetString(string(n))
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
 	}
-	return string(b1) == string(b2)
+	return nil, false
 }
@@ -0,0 +1,61 @@
+package comparator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKeysOnlyTextDiffIsDeterministic(t *testing.T) {
+	original := []byte(`{"zeta": 1, "alpha": {"mu": "x", "beta": [1, 2]}, "kappa": null, "delta": true}`)
+	modified := []byte(`{"delta": "yes", "kappa": {}, "alpha": {"beta": [1], "nu": 3}, "omega": 2}`)
+	opts := CompareOptions{KeysOnly: true}
+
+	first, err := CompareWithOptions(original, modified, "a", "b", opts)
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if first.TextDiff == "" {
+		t.Fatal("expected a keys-only text diff")
+	}
+	for i := 0; i < 20; i++ {
+		again, err := CompareWithOptions(original, modified, "a", "b", opts)
+		if err != nil {
+			t.Fatalf("CompareWithOptions: %v", err)
+		}
+		if again.TextDiff != first.TextDiff {
+			t.Fatalf("keys-only text diff differs between runs:\n%s\nvs\n%s", first.TextDiff, again.TextDiff)
+		}
+	}
+}
+
+func TestDeepEqual(t *testing.T) {
+	tests := []struct {
+		name   string
+		v1, v2 interface{}
+		want   bool
+	}{
+		{"int and float", 1, 1.0, true},
+		{"json.Number and float", json.Number("1.0"), 1.0, true},
+		{"json.Number exponent", json.Number("1e2"), json.Number("100"), true},
+		{"different numbers", json.Number("1"), 1.5, false},
+		{"number and string", 1.0, "1", false},
+		{"nested maps", map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1.0, "x"}}},
+			map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{json.Number("1"), "x"}}}, true},
+		{"nested maps differ", map[string]interface{}{"a": map[string]interface{}{"b": 1.0}},
+			map[string]interface{}{"a": map[string]interface{}{"b": 2.0}}, false},
+		{"nil and missing key", map[string]interface{}{"a": nil}, map[string]interface{}{"b": nil}, false},
+		{"nil and absent key", map[string]interface{}{"a": 1.0, "b": nil}, map[string]interface{}{"a": 1.0}, false},
+		{"nil and nil", nil, nil, true},
+		{"nil and false", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deepEqual(tt.v1, tt.v2); got != tt.want {
+				t.Errorf("deepEqual(%v, %v) = %v, want %v", tt.v1, tt.v2, got, tt.want)
+			}
+			if got := deepEqual(tt.v2, tt.v1); got != tt.want {
+				t.Errorf("deepEqual(%v, %v) = %v, want %v", tt.v2, tt.v1, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package comparator
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// IsEmptyResponse reports whether a response body is empty or only whitespace
+func IsEmptyResponse(body []byte) bool {
+	return len(bytes.TrimSpace(body)) == 0
+}
+
+// CompareEmpty describes a comparison where at least one response is empty
+// (see IsEmptyResponse), naming the versions, e.g. "v2 returned empty
+// response (0 bytes) while v1 returned 512 bytes". Two empty responses are
+// not a change. No text diff is produced: it would only list the other
+// response's lines.
+func CompareEmpty(original, modified []byte, name1, name2 string) *DiffResult {
+	result := &DiffResult{
+		JsonPatch:     []byte("[]"),
+		Summary:       NoChangesSummary,
+		OriginalEmpty: IsEmptyResponse(original),
+		ModifiedEmpty: IsEmptyResponse(modified),
+	}
+	switch {
+	case result.OriginalEmpty && !result.ModifiedEmpty:
+		result.Summary = fmt.Sprintf("%s returned empty response (%d bytes) while %s returned %d bytes",
+			name1, len(original), name2, len(modified))
+	case result.ModifiedEmpty && !result.OriginalEmpty:
+		result.Summary = fmt.Sprintf("%s returned empty response (%d bytes) while %s returned %d bytes",
+			name2, len(modified), name1, len(original))
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package comparator
+
+import "testing"
+
+func TestCompareEmpty(t *testing.T) {
+	tests := []struct {
+		name                  string
+		original, modified    string
+		wantSummary           string
+		wantOriginal, wantMod bool
+	}{
+		{"both empty", "", " \n", NoChangesSummary, true, true},
+		{"original empty", "", `{"id": 1}`, "v1 returned empty response (0 bytes) while v2 returned 9 bytes", true, false},
+		{"modified empty", `{"id": 1}`, "\n", "v2 returned empty response (1 bytes) while v1 returned 9 bytes", false, true},
+		// Callers only use CompareEmpty when IsEmptyResponse holds for either side
+		{"neither empty", `{"id": 1}`, `{"id": 2}`, "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CompareEmpty([]byte(tt.original), []byte(tt.modified), "v1", "v2")
+			if tt.wantSummary != "" && result.Summary != tt.wantSummary {
+				t.Errorf("Summary = %q, want %q", result.Summary, tt.wantSummary)
+			}
+			if result.OriginalEmpty != tt.wantOriginal || result.ModifiedEmpty != tt.wantMod {
+				t.Errorf("OriginalEmpty, ModifiedEmpty = %v, %v; want %v, %v",
+					result.OriginalEmpty, result.ModifiedEmpty, tt.wantOriginal, tt.wantMod)
+			}
+			if result.TextDiff != "" {
+				t.Errorf("TextDiff = %q, want none", result.TextDiff)
+			}
+		})
+	}
+}
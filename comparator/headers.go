@@ -0,0 +1,77 @@
+package comparator
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HeaderDiff describes the differences between two sets of HTTP headers or trailers
+type HeaderDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Summary string   `json:"summary"`
+}
+
+// HasChanges reports whether any header differs
+func (h *HeaderDiff) HasChanges() bool {
+	return len(h.Added)+len(h.Removed)+len(h.Changed) > 0
+}
+
+// CompareHeaders diffs two header maps. Names are compared case-insensitively
+// and names in ignore are skipped. kind ("header" or "trailer") is used in the summary.
+func CompareHeaders(original, modified map[string]string, ignore []string, kind string) *HeaderDiff {
+	skip := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		skip[http.CanonicalHeaderKey(name)] = true
+	}
+
+	h1 := canonicalHeaders(original, skip)
+	h2 := canonicalHeaders(modified, skip)
+
+	label := strings.ToUpper(kind[:1]) + kind[1:]
+	diff := &HeaderDiff{}
+	var changes []string
+	for name, v1 := range h1 {
+		v2, ok := h2[name]
+		if !ok {
+			diff.Removed = append(diff.Removed, name)
+			changes = append(changes, fmt.Sprintf("%s '%s' removed", label, name))
+		} else if v1 != v2 {
+			diff.Changed = append(diff.Changed, name)
+			changes = append(changes, fmt.Sprintf("%s '%s' changed", label, name))
+		}
+	}
+	for name := range h2 {
+		if _, ok := h1[name]; !ok {
+			diff.Added = append(diff.Added, name)
+			changes = append(changes, fmt.Sprintf("%s '%s' added", label, name))
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	if len(changes) == 0 {
+		diff.Summary = fmt.Sprintf("No %s changes", kind)
+	} else {
+		sort.Strings(changes)
+		diff.Summary = strings.Join(changes, ", ")
+	}
+	return diff
+}
+
+// canonicalHeaders returns a copy of headers with canonical names, minus skipped ones
+func canonicalHeaders(headers map[string]string, skip map[string]bool) map[string]string {
+	result := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = http.CanonicalHeaderKey(name)
+		if !skip[name] {
+			result[name] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package comparator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ValidatePath reports whether path is a valid dot/bracket path such as
+// "data.requestId" or "items[].createdAt"
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+// ValidateIgnorePath reports whether path is a valid ignored path: a
+// dot/bracket path, or a glob in the mask_rules path syntax ("*" within a
+// key, "[*]" any index, "**" any number of segments)
+func ValidateIgnorePath(path string) error {
+	if isGlob(path) {
+		_, err := splitGlob(path)
+		return err
+	}
+	return ValidatePath(path)
+}
+
+// isGlob reports whether an ignored path uses glob wildcards
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?")
+}
+
+// pruneIgnored removes every node matching one of the paths. Paths that
+// don't exist or fail to parse are ignored; "[]" matches every element and
+// globs (see ValidateIgnorePath) every node they match.
+func pruneIgnored(v interface{}, paths []string) interface{} {
+	for _, path := range paths {
+		if isGlob(path) {
+			if glob, err := splitGlob(path); err == nil {
+				v = pruneGlob(v, nil, glob)
+			}
+			continue
+		}
+		segments, err := parsePath(path)
+		if err != nil || len(segments) == 0 {
+			continue
+		}
+		v = prunePath(v, segments)
+	}
+	return v
+}
+
+// pruneGlob returns v with every node below the concrete path at that
+// matches glob removed, tracking the path as key and "[N]" tokens like
+// maskValue. Maps are modified in place; arrays are rebuilt.
+func pruneGlob(v interface{}, at []string, glob []string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			childAt := append(at, k)
+			if globMatch(glob, childAt) {
+				delete(val, k)
+			} else {
+				val[k] = pruneGlob(child, childAt, glob)
+			}
+		}
+		return val
+	case []interface{}:
+		kept := make([]interface{}, 0, len(val))
+		for i, child := range val {
+			childAt := append(at, "["+strconv.Itoa(i)+"]")
+			if !globMatch(glob, childAt) {
+				kept = append(kept, pruneGlob(child, childAt, glob))
+			}
+		}
+		return kept
+	}
+	return v
+}
+
+// prunePath returns v with the node at segments removed. Maps are modified in
+// place; arrays are rebuilt when an element is removed.
+func prunePath(v interface{}, segments []pathSegment) interface{} {
+	seg, rest := segments[0], segments[1:]
+
+	if !seg.IsIndex {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		child, ok := m[seg.Key]
+		if !ok {
+			return v
+		}
+		if len(rest) == 0 {
+			delete(m, seg.Key)
+		} else {
+			m[seg.Key] = prunePath(child, rest)
+		}
+		return m
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	switch {
+	case seg.AnyIndex && len(rest) == 0:
+		return []interface{}{}
+	case seg.AnyIndex:
+		for i, item := range arr {
+			arr[i] = prunePath(item, rest)
+		}
+	case seg.Index >= len(arr):
+	case len(rest) == 0:
+		pruned := make([]interface{}, 0, len(arr)-1)
+		pruned = append(pruned, arr[:seg.Index]...)
+		return append(pruned, arr[seg.Index+1:]...)
+	default:
+		arr[seg.Index] = prunePath(arr[seg.Index], rest)
+	}
+	return arr
+}
@@ -0,0 +1,39 @@
+package comparator
+
+import "testing"
+
+func TestIgnoreGlobsPruneMatches(t *testing.T) {
+	original := []byte(`{"meta": {"trace": "a", "took": 3}, "items": [{"id": 1, "createdAt": "x"}], "user": {"createdAt": "y", "name": "alice"}}`)
+	modified := []byte(`{"meta": {"trace": "b", "took": 9}, "items": [{"id": 2, "createdAt": "z"}], "user": {"createdAt": "w", "name": "alice"}}`)
+
+	opts := CompareOptions{IgnorePaths: []string{"**.createdAt", "meta.*", "items[*].id"}}
+	result, err := CompareWithOptions(original, modified, "a", "b", opts)
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if result.Summary != NoChangesSummary {
+		t.Errorf("summary = %q, diff:\n%s\nwant no changes", result.Summary, result.TextDiff)
+	}
+
+	modified = []byte(`{"meta": {}, "items": [{"id": 2, "createdAt": "z"}], "user": {"createdAt": "w", "name": "bob"}}`)
+	result, err = CompareWithOptions(original, modified, "a", "b", opts)
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if result.Summary == NoChangesSummary {
+		t.Error("a change outside the ignore globs was not reported")
+	}
+}
+
+func TestValidateIgnorePath(t *testing.T) {
+	for _, path := range []string{"data.id", "items[].createdAt", "**.createdAt", "meta.*"} {
+		if err := ValidateIgnorePath(path); err != nil {
+			t.Errorf("ValidateIgnorePath(%q) = %v", path, err)
+		}
+	}
+	for _, path := range []string{"items[x]", "a..*"} {
+		if err := ValidateIgnorePath(path); err == nil {
+			t.Errorf("ValidateIgnorePath(%q) accepted an invalid path", path)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package comparator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxLCSCells bounds the table used to align two arrays (after trimming their
+// common prefix and suffix); larger arrays get a positional summary instead
+const maxLCSCells = 1 << 20
+
+// arrayEdit counts how the elements of one array became those of another
+type arrayEdit struct {
+	added, removed, changed, moved, unchanged int
+}
+
+// summary describes the edit, e.g. "Array: 2 items added, 1 removed,
+// 3 unchanged, order preserved"
+func (e arrayEdit) summary() string {
+	var parts []string
+	for _, part := range []struct {
+		count int
+		kind  string
+	}{
+		{e.added, "added"},
+		{e.removed, "removed"},
+		{e.changed, "changed"},
+		{e.moved, "moved"},
+		{e.unchanged, "unchanged"},
+	} {
+		if part.count == 0 && part.kind != "unchanged" {
+			continue
+		}
+		if len(parts) == 0 {
+			noun := "items"
+			if part.count == 1 {
+				noun = "item"
+			}
+			parts = append(parts, fmt.Sprintf("%d %s %s", part.count, noun, part.kind))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", part.count, part.kind))
+		}
+	}
+
+	order := "order preserved"
+	if e.moved > 0 {
+		order = "order changed"
+	}
+	return "Array: " + strings.Join(parts, ", ") + ", " + order
+}
+
+// diffArrays aligns two arrays with a longest common subsequence over their
+// serialized elements. Elements outside the subsequence that appear on both
+// sides count as moved; within each gap between aligned elements, removals
+// and additions pair up as changed elements. It returns false when the arrays
+// are too large to align.
+func diffArrays(arr1, arr2 []interface{}) (arrayEdit, bool) {
+	keys1, keys2 := serializeElements(arr1), serializeElements(arr2)
+
+	// Common prefix and suffix are unchanged and need no table
+	start := 0
+	for start < len(keys1) && start < len(keys2) && keys1[start] == keys2[start] {
+		start++
+	}
+	end1, end2 := len(keys1), len(keys2)
+	for end1 > start && end2 > start && keys1[end1-1] == keys2[end2-1] {
+		end1--
+		end2--
+	}
+	a, b := keys1[start:end1], keys2[start:end2]
+	if len(a)*len(b) > maxLCSCells {
+		return arrayEdit{}, false
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:], b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Walk the table, collecting the unaligned elements between aligned ones
+	type gap struct{ removed, added []string }
+	gaps := []gap{{}}
+	edit := arrayEdit{unchanged: start + len(keys1) - end1}
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		last := &gaps[len(gaps)-1]
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edit.unchanged++
+			gaps = append(gaps, gap{})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			last.added = append(last.added, b[j])
+			j++
+		default:
+			last.removed = append(last.removed, a[i])
+			i++
+		}
+	}
+
+	// An element removed in one gap and added in another was moved
+	removed := make(map[string]int)
+	for _, g := range gaps {
+		for _, key := range g.removed {
+			removed[key]++
+		}
+	}
+	movedOut := make(map[string]int)
+	for _, g := range gaps {
+		for _, key := range g.added {
+			if removed[key] > 0 {
+				removed[key]--
+				movedOut[key]++
+				edit.moved++
+			}
+		}
+	}
+	movedIn := make(map[string]int, len(movedOut))
+	for key, n := range movedOut {
+		movedIn[key] = n
+	}
+
+	for _, g := range gaps {
+		r, ad := 0, 0
+		for _, key := range g.removed {
+			if movedOut[key] > 0 {
+				movedOut[key]--
+			} else {
+				r++
+			}
+		}
+		for _, key := range g.added {
+			if movedIn[key] > 0 {
+				movedIn[key]--
+			} else {
+				ad++
+			}
+		}
+		changed := min(r, ad)
+		edit.changed += changed
+		edit.removed += r - changed
+		edit.added += ad - changed
+	}
+	return edit, true
+}
+
+// serializeElements serializes array elements for comparison; json.Marshal
+// sorts object keys, so equal elements serialize identically
+func serializeElements(arr []interface{}) []string {
+	keys := make([]string, len(arr))
+	for i, v := range arr {
+		data, err := json.Marshal(v)
+		if err != nil {
+			keys[i] = fmt.Sprint(v)
+			continue
+		}
+		keys[i] = string(data)
+	}
+	return keys
+}
@@ -0,0 +1,40 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeArrayDifferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		arr1, arr2 []interface{}
+		want       string
+	}{
+		{"front insertion", []interface{}{1.0, 2.0, 3.0}, []interface{}{0.0, 1.0, 2.0, 3.0}, "Array: 1 item added, 3 unchanged, order preserved"},
+		{"removal", []interface{}{1.0, 2.0, 3.0}, []interface{}{1.0, 3.0}, "Array: 1 item removed, 2 unchanged, order preserved"},
+		{"reorder", []interface{}{1.0, 2.0, 3.0}, []interface{}{3.0, 1.0, 2.0}, "Array: 1 item moved, 2 unchanged, order changed"},
+		{"change", []interface{}{1.0, 2.0, 3.0}, []interface{}{1.0, 5.0, 3.0}, "Array: 1 item changed, 2 unchanged, order preserved"},
+		{"equal", []interface{}{1.0, 2.0}, []interface{}{1.0, 2.0}, NoChangesSummary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeArrayDifferences(tt.arr1, tt.arr2); got != tt.want {
+				t.Errorf("summarizeArrayDifferences() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamedArraySummaryIsMarkedPositional(t *testing.T) {
+	original := strings.NewReader(`[1, 2, 3]`)
+	modified := strings.NewReader(`[0, 1, 2, 3]`)
+
+	result, err := CompareArrayStreams(original, modified, "a", "b", CompareOptions{})
+	if err != nil {
+		t.Fatalf("CompareArrayStreams: %v", err)
+	}
+	if want := "Array length changed: 3 → 4 items (compared by position)"; result.Summary != want {
+		t.Errorf("Summary = %q, want %q", result.Summary, want)
+	}
+}
@@ -0,0 +1,184 @@
+package comparator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaskToken replaces masked values when a rule sets no token
+const DefaultMaskToken = "<MASKED>"
+
+// MaskRule normalizes unpredictable string values (UUIDs, signed URLs) so only
+// changes in their shape are reported
+type MaskRule struct {
+	// Path is a glob over JSON paths: "*" matches one key (or part of one,
+	// e.g. "user*"), "[]" any array index and "**" any number of segments,
+	// e.g. "data.items[].id" or "**.url"
+	Path string `json:"path"`
+
+	// Pattern is a regular expression; every match within a string value at
+	// Path is replaced with Token
+	Pattern string `json:"pattern"`
+
+	// Token is the canonical replacement (default "<MASKED>")
+	Token string `json:"token,omitempty"`
+}
+
+// compiledMask is a MaskRule ready to apply
+type compiledMask struct {
+	glob    []string
+	pattern *regexp.Regexp
+	token   string
+}
+
+// ValidateMaskRule reports whether a rule's path glob and pattern are valid
+func ValidateMaskRule(rule MaskRule) error {
+	_, err := compileMask(rule)
+	return err
+}
+
+func compileMask(rule MaskRule) (compiledMask, error) {
+	glob, err := splitGlob(rule.Path)
+	if err != nil {
+		return compiledMask{}, err
+	}
+	if rule.Pattern == "" {
+		return compiledMask{}, fmt.Errorf("pattern cannot be empty")
+	}
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return compiledMask{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	token := rule.Token
+	if token == "" {
+		token = DefaultMaskToken
+	}
+	return compiledMask{glob: glob, pattern: re, token: token}, nil
+}
+
+// splitGlob splits a path glob into key and "[...]" tokens
+func splitGlob(glob string) ([]string, error) {
+	glob = strings.TrimSpace(glob)
+	if glob == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+	var tokens []string
+	for _, part := range strings.Split(glob, ".") {
+		if part == "" {
+			return nil, fmt.Errorf("invalid path %q: empty segment", glob)
+		}
+		key, rest := part, ""
+		if idx := strings.Index(part, "["); idx >= 0 {
+			key, rest = part[:idx], part[idx:]
+		}
+		if key != "" {
+			if _, err := path.Match(key, ""); err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", glob, err)
+			}
+			tokens = append(tokens, key)
+		}
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid path %q: malformed brackets", glob)
+			}
+			tokens = append(tokens, rest[:end+1])
+			rest = rest[end+1:]
+		}
+	}
+	return tokens, nil
+}
+
+// applyMasks returns v with every rule applied; invalid rules are skipped
+func applyMasks(v interface{}, rules []MaskRule) interface{} {
+	var masks []compiledMask
+	for _, rule := range rules {
+		if m, err := compileMask(rule); err == nil {
+			masks = append(masks, m)
+		}
+	}
+	if len(masks) == 0 {
+		return v
+	}
+	return maskValue(v, nil, masks)
+}
+
+// maskValue walks v, tracking the concrete path as key and "[N]" tokens
+func maskValue(v interface{}, at []string, masks []compiledMask) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = maskValue(child, append(at, k), masks)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = maskValue(child, append(at, "["+strconv.Itoa(i)+"]"), masks)
+		}
+		return val
+	case string:
+		for _, m := range masks {
+			if globMatch(m.glob, at) {
+				val = m.pattern.ReplaceAllString(val, m.token)
+			}
+		}
+		return val
+	}
+	return v
+}
+
+// globMatch reports whether a concrete path matches a glob
+func globMatch(glob, at []string) bool {
+	if len(glob) == 0 {
+		return len(at) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(at); i++ {
+			if globMatch(glob[1:], at[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(at) == 0 {
+		return false
+	}
+
+	g, seg := glob[0], at[0]
+	globIndex := strings.HasPrefix(g, "[")
+	segIndex := strings.HasPrefix(seg, "[")
+	switch {
+	case globIndex != segIndex:
+		return false
+	case globIndex:
+		if g != "[]" && g != "[*]" && g != seg {
+			return false
+		}
+	default:
+		if ok, _ := path.Match(g, seg); !ok {
+			return false
+		}
+	}
+	return globMatch(glob[1:], at[1:])
+}
+
+// indentJSON marshals v like json.MarshalIndent but keeps tokens such as
+// "<UUID>" readable instead of escaping them as \u003c. encoding/json writes
+// map keys in sorted order, so re-marshalled documents (keys-only, masked,
+// canonicalized) give byte-identical text diffs from run to run; keep that
+// guarantee if this is ever replaced by a custom encoder.
+func indentJSON(v interface{}) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
@@ -0,0 +1,41 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+)
+
+var uuidMask = MaskRule{
+	Path:    "**.id",
+	Pattern: `[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`,
+	Token:   "<UUID>",
+}
+
+func TestMaskedUUIDsCompareEqual(t *testing.T) {
+	original := []byte(`{"user": {"id": "0b6c6d1e-4a2f-4c1d-9e0a-3f5b7c9d1e2f", "name": "alice"}}`)
+	modified := []byte(`{"user": {"id": "9f8e7d6c-5b4a-4321-8fed-cba987654321", "name": "alice"}}`)
+
+	result, err := CompareWithOptions(original, modified, "a", "b", CompareOptions{MaskRules: []MaskRule{uuidMask}})
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if result.Summary != NoChangesSummary || result.TextDiff != "" {
+		t.Errorf("summary = %q, diff:\n%s\nwant no changes", result.Summary, result.TextDiff)
+	}
+}
+
+func TestMaskedUUIDAgainstNullIsChange(t *testing.T) {
+	original := []byte(`{"user": {"id": "0b6c6d1e-4a2f-4c1d-9e0a-3f5b7c9d1e2f"}}`)
+	modified := []byte(`{"user": {"id": null}}`)
+
+	result, err := CompareWithOptions(original, modified, "a", "b", CompareOptions{MaskRules: []MaskRule{uuidMask}})
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if result.Summary == NoChangesSummary {
+		t.Fatal("a masked UUID replaced by null was not reported")
+	}
+	if !strings.Contains(result.TextDiff, "<UUID>") {
+		t.Errorf("text diff does not show the mask token:\n%s", result.TextDiff)
+	}
+}
@@ -0,0 +1,154 @@
+package comparator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MultiDiffResult compares the same response across several versions at
+// once, field by field, instead of as pairwise diffs
+type MultiDiffResult struct {
+	// Versions lists the compared versions in column order (sorted by name)
+	Versions []string `json:"versions"`
+
+	// Fields lists the leaf paths whose value is not the same in every
+	// version, sorted by path. TotalFields counts every distinct leaf path.
+	Fields      []MultiField `json:"fields"`
+	TotalFields int          `json:"total_fields"`
+
+	Summary string `json:"summary"`
+}
+
+// MultiField is one leaf path and its value in each version
+type MultiField struct {
+	Path string `json:"path"`
+
+	// Values maps a version to its value at the path, as compact JSON;
+	// versions where the path is missing are absent
+	Values map[string]string `json:"values"`
+
+	// Differs lists the versions whose value differs from the most common
+	// one (ties go to the value of the earliest version)
+	Differs []string `json:"differs"`
+}
+
+// HasChanges reports whether any field differs between the versions
+func (m *MultiDiffResult) HasChanges() bool {
+	return len(m.Fields) > 0
+}
+
+// multiMissing stands for a missing path when counting values
+const multiMissing = "\x00missing"
+
+// CompareMulti compares the JSON responses of several versions, keyed by
+// version name. Prefix stripping, masking, ignored paths, array sorting and
+// keys-only mode apply as in CompareWithOptions; ArrayKeys, CardinalityPaths
+// and LabelPath are pairwise options and are not used. Every input must be
+// valid JSON.
+func CompareMulti(inputs map[string][]byte, opts CompareOptions) (*MultiDiffResult, error) {
+	if len(inputs) < 2 {
+		return nil, fmt.Errorf("multi comparison needs at least two versions, got %d", len(inputs))
+	}
+
+	result := &MultiDiffResult{Fields: []MultiField{}}
+	for version := range inputs {
+		result.Versions = append(result.Versions, version)
+	}
+	sort.Strings(result.Versions)
+
+	leaves := make(map[string]map[string]interface{}, len(inputs))
+	paths := make(map[string]bool)
+	for _, version := range result.Versions {
+		v, err := prepareMulti(inputs[version], opts)
+		if err != nil {
+			return nil, fmt.Errorf("response from %s: %w", version, err)
+		}
+		flat := make(map[string]interface{})
+		flattenLeaves(v, "", flat)
+		for path := range flat {
+			paths[path] = true
+		}
+		leaves[version] = flat
+	}
+	result.TotalFields = len(paths)
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	for _, path := range sorted {
+		field := MultiField{Path: path, Values: make(map[string]string)}
+		rendered := make([]string, len(result.Versions))
+		counts := make(map[string]int)
+		for i, version := range result.Versions {
+			rendered[i] = multiMissing
+			if value, ok := leaves[version][path]; ok {
+				rendered[i] = renderMultiValue(value)
+				field.Values[version] = rendered[i]
+			}
+			counts[rendered[i]]++
+		}
+		if counts[rendered[0]] == len(rendered) {
+			continue
+		}
+
+		common := rendered[0]
+		for _, value := range rendered {
+			if counts[value] > counts[common] {
+				common = value
+			}
+		}
+		for i, version := range result.Versions {
+			if rendered[i] != common {
+				field.Differs = append(field.Differs, version)
+			}
+		}
+		result.Fields = append(result.Fields, field)
+	}
+
+	if len(result.Fields) == 0 {
+		result.Summary = NoChangesSummary
+	} else {
+		result.Summary = fmt.Sprintf("%d of %d field(s) differ across %d versions",
+			len(result.Fields), result.TotalFields, len(result.Versions))
+	}
+	return result, nil
+}
+
+// prepareMulti decodes one response and applies the options that don't
+// depend on the other side
+func prepareMulti(data []byte, opts CompareOptions) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(stripJSONPrefix(data, opts.StripPrefixes), &v); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	if len(opts.MaskRules) > 0 {
+		v = applyMasks(v, opts.MaskRules)
+	}
+	if len(opts.IgnorePaths) > 0 {
+		v = pruneIgnored(v, opts.IgnorePaths)
+	}
+	if opts.SortArrays {
+		v = sortScalarArrays(v)
+	}
+	if opts.KeysOnly {
+		v = extractKeys(v)
+	}
+	return v, nil
+}
+
+// renderMultiValue renders a leaf value as compact JSON
+func renderMultiValue(v interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,161 @@
+package comparator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/wI2L/jsondiff"
+)
+
+// parseNDJSON decodes newline-delimited JSON: one JSON value per non-empty
+// line. ok is false if any line is not valid JSON.
+func parseNDJSON(data []byte) (records []interface{}, lines []string, ok bool) {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			return nil, nil, false
+		}
+		records = append(records, v)
+		lines = append(lines, line)
+	}
+	return records, lines, len(records) > 0
+}
+
+// isNDJSON reports whether both inputs are newline-delimited JSON and at
+// least one holds several records (a single value is plain JSON)
+func isNDJSON(original, modified []byte) bool {
+	a, _, okA := parseNDJSON(original)
+	b, _, okB := parseNDJSON(modified)
+	return okA && okB && (len(a) > 1 || len(b) > 1)
+}
+
+// compareAsNDJSON compares newline-delimited JSON record by record. Records
+// are matched by position; the summary names the fields that changed in each
+// record, e.g. "record 2 changed: field 'status'".
+func compareAsNDJSON(original, modified []byte, name1, name2 string, opts CompareOptions) (*DiffResult, error) {
+	records1, lines1, _ := parseNDJSON(original)
+	records2, lines2, _ := parseNDJSON(modified)
+
+	for i := range records1 {
+		records1[i] = prepareRecord(records1[i], opts)
+		lines1[i] = compactJSON(records1[i])
+	}
+	for i := range records2 {
+		records2[i] = prepareRecord(records2[i], opts)
+		lines2[i] = compactJSON(records2[i])
+	}
+
+	// One line per record, so hunks point at the records that differ
+	diff := difflib.UnifiedDiff{
+		A:        joinLines(lines1),
+		B:        joinLines(lines2),
+		FromFile: name1,
+		ToFile:   name2,
+		Context:  1,
+	}
+	textDiff, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		textDiff = fmt.Sprintf("Failed to create diff: %v", err)
+	}
+
+	patch, err := jsondiff.Compare(records1, records2)
+	if err != nil {
+		return nil, fmt.Errorf("jsondiff failed: %w", err)
+	}
+	sortPatch(patch)
+	patchBytes, err := json.MarshalIndent(patch, "", "  ")
+	if err != nil {
+		patchBytes = []byte("[]")
+	}
+
+	var changes []string
+	for i := 0; i < len(records1) || i < len(records2); i++ {
+		n := i + 1
+		switch {
+		case i >= len(records2):
+			changes = append(changes, fmt.Sprintf("record %d removed", n))
+		case i >= len(records1):
+			changes = append(changes, fmt.Sprintf("record %d added", n))
+		default:
+			changes = append(changes, recordChanges(n, records1[i], records2[i])...)
+		}
+	}
+	summary := NoChangesSummary
+	if len(changes) > 0 {
+		summary = strings.Join(changes, ", ")
+	}
+
+	leafChanges, totalFields := diffLeaves(records1, records2)
+	return &DiffResult{
+		TextDiff:      textDiff,
+		JsonPatch:     patchBytes,
+		Summary:       summary,
+		IsJSON:        true,
+		ChangedFields: len(leafChanges),
+		TotalFields:   totalFields,
+		Changes:       newChangeSet(leafChanges),
+	}, nil
+}
+
+// prepareRecord applies the value-level options (masks, ignored paths,
+// keys-only) to a single record
+func prepareRecord(v interface{}, opts CompareOptions) interface{} {
+	if len(opts.MaskRules) > 0 {
+		v = applyMasks(v, opts.MaskRules)
+	}
+	if len(opts.IgnorePaths) > 0 {
+		v = pruneIgnored(v, opts.IgnorePaths)
+	}
+	if opts.KeysOnly {
+		v = extractKeys(v)
+	}
+	return v
+}
+
+// recordChanges describes how one record differs, naming changed fields when
+// both records are objects
+func recordChanges(n int, a, b interface{}) []string {
+	if deepEqual(a, b) {
+		return nil
+	}
+	_, isMapA := a.(map[string]interface{})
+	_, isMapB := b.(map[string]interface{})
+	if !isMapA || !isMapB {
+		return []string{fmt.Sprintf("record %d changed", n)}
+	}
+
+	var out []string
+	collectChanges(a, b, "", 0, nil, func(path, kind string, _, _ interface{}) {
+		out = append(out, fmt.Sprintf("record %d %s: field '%s'", n, kind, path))
+	})
+	sort.Strings(out)
+	return out
+}
+
+// compactJSON marshals v on a single line
+func compactJSON(v interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// joinLines turns lines into difflib input, each terminated by a newline
+func joinLines(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = line + "\n"
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareNDJSON(t *testing.T) {
+	original := []byte(`{"id": 1, "status": "ok"}
+{"id": 2, "status": "ok"}
+{"id": 3, "status": "ok"}
+`)
+	modified := []byte(`{"id": 1, "status": "ok"}
+{"id": 2, "status": "failed"}
+{"id": 3, "status": "ok"}
+`)
+
+	result, err := Compare(original, modified, "a", "b")
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if !strings.Contains(result.Summary, "record 2 changed: field 'status'") {
+		t.Errorf("Summary = %q, want record 2's status reported", result.Summary)
+	}
+	if strings.Contains(result.Summary, "record 1") || strings.Contains(result.Summary, "record 3") {
+		t.Errorf("Summary = %q, reports unchanged records", result.Summary)
+	}
+	if !strings.Contains(result.TextDiff, `+{"id":2,"status":"failed"}`) {
+		t.Errorf("text diff does not show the changed record:\n%s", result.TextDiff)
+	}
+}
@@ -0,0 +1,65 @@
+package comparator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wI2L/jsondiff"
+)
+
+// sortPatch orders patch operations deterministically by path, then op type,
+// so identical inputs always produce byte-identical patches.
+//
+// Reordering must not change what the patch does when applied:
+//   - Operations addressing array elements depend on each other (removing
+//     index 1 shifts index 3), so all operations under the same array keep
+//     their original relative order and are sorted as a single group.
+//   - move/copy operations read from another location; if any are present
+//     the patch is left untouched.
+func sortPatch(patch jsondiff.Patch) {
+	for _, op := range patch {
+		if op.Type == jsondiff.OperationMove || op.Type == jsondiff.OperationCopy {
+			return
+		}
+	}
+
+	sort.SliceStable(patch, func(i, j int) bool {
+		pathI, opI := patchSortKey(patch[i])
+		pathJ, opJ := patchSortKey(patch[j])
+		if pathI != pathJ {
+			return pathI < pathJ
+		}
+		return opI < opJ
+	})
+}
+
+// patchSortKey returns the sort key for an operation. For operations inside
+// an array the key is the array's path and the op type is omitted, so the
+// stable sort preserves their original order.
+func patchSortKey(op jsondiff.Operation) (string, string) {
+	segments := strings.Split(op.Path, "/")
+	for i, seg := range segments {
+		if i > 0 && isArrayIndexToken(seg) {
+			return strings.Join(segments[:i], "/"), ""
+		}
+	}
+	return op.Path, op.Type
+}
+
+// isArrayIndexToken reports whether a JSON Pointer token addresses an array
+// element ("-" appends). Numeric object keys are treated conservatively as
+// array indices, which only means they are not reordered.
+func isArrayIndexToken(token string) bool {
+	if token == "-" {
+		return true
+	}
+	if token == "" {
+		return false
+	}
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package comparator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single step in a JSON path such as "data.items[0].id"
+type pathSegment struct {
+	Key      string // Object key (empty for index segments)
+	Index    int    // Array index when IsIndex is set
+	IsIndex  bool   // Segment addresses an array element
+	AnyIndex bool   // Segment is "[]" and addresses every array element
+}
+
+// parsePath splits a dot/bracket path into segments.
+// Supported forms: "a.b", "a[0].b", "a[].b" and a leading "[0]" for top-level arrays.
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return segments, nil
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return nil, fmt.Errorf("invalid path %q: empty segment", path)
+		}
+
+		// Leading key before any brackets
+		key := part
+		rest := ""
+		if idx := strings.Index(part, "["); idx >= 0 {
+			key, rest = part[:idx], part[idx:]
+		}
+		if key != "" {
+			segments = append(segments, pathSegment{Key: key})
+		}
+
+		// Any number of [N] or [] suffixes
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid path %q: malformed brackets", path)
+			}
+			inner := rest[1:end]
+			if inner == "" {
+				segments = append(segments, pathSegment{IsIndex: true, AnyIndex: true})
+			} else {
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid path %q: bad index %q", path, inner)
+				}
+				segments = append(segments, pathSegment{IsIndex: true, Index: n})
+			}
+			rest = rest[end+1:]
+		}
+	}
+
+	return segments, nil
+}
+
+// lookupPath returns the value at path in v. For "[]" segments the first
+// element is used. The boolean is false when the path does not exist.
+func lookupPath(v interface{}, path string) (interface{}, bool) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	current := v
+	for _, seg := range segments {
+		if seg.IsIndex {
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			idx := seg.Index
+			if seg.AnyIndex {
+				idx = 0
+			}
+			if idx >= len(arr) {
+				return nil, false
+			}
+			current = arr[idx]
+			continue
+		}
+
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		child, ok := m[seg.Key]
+		if !ok {
+			return nil, false
+		}
+		current = child
+	}
+	return current, true
+}
+
+// LookupPath returns the value at a dot/bracket path (e.g. "data.items[0].id")
+// in a decoded JSON document. The boolean is false when the path does not exist.
+func LookupPath(v interface{}, path string) (interface{}, bool) {
+	return lookupPath(v, path)
+}
+
+// formatValue renders a scalar JSON value for use in human-readable summaries
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// indexSegment matches a concrete array index in a path
+var indexSegment = regexp.MustCompile(`\[\d+\]`)
+
+// WildcardPath replaces array indexes in a path with "[]", turning a
+// concrete path such as "items[3].price" into the ignore_paths form
+// "items[].price" that matches every element
+func WildcardPath(path string) string {
+	return indexSegment.ReplaceAllString(path, "[]")
+}
+
+// PathMatches reports whether the concrete path of a change (e.g.
+// "items[3].price") equals or lies under pattern, where "[]" in the pattern
+// matches any array index: "items[].price" and "items" both match
+func PathMatches(pattern, path string) bool {
+	for _, candidate := range []string{path, WildcardPath(path)} {
+		if candidate == pattern || strings.HasPrefix(candidate, pattern+".") || strings.HasPrefix(candidate, pattern+"[") {
+			return true
+		}
+	}
+	return false
+}
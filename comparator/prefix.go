@@ -0,0 +1,46 @@
+package comparator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// XSSIPrefix is the anti-XSSI guard some APIs put before JSON responses
+const XSSIPrefix = ")]}',\n"
+
+// utf8BOM is the byte order mark some servers write before UTF-8 bodies
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// stripJSONPrefix returns data without a leading UTF-8 byte order mark and
+// the first of prefixes it then starts with, so the JSON after them can be
+// parsed. data itself is not modified.
+func stripJSONPrefix(data []byte, prefixes []string) []byte {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	for _, prefix := range prefixes {
+		if prefix != "" && bytes.HasPrefix(data, []byte(prefix)) {
+			return data[len(prefix):]
+		}
+	}
+	return data
+}
+
+// stripReaderPrefix is stripJSONPrefix for a stream: it returns a reader
+// positioned after the byte order mark and prefix, if present
+func stripReaderPrefix(r io.Reader, prefixes []string) (io.Reader, error) {
+	longest := len(utf8BOM)
+	for _, prefix := range prefixes {
+		longest = max(longest, len(utf8BOM)+len(prefix))
+	}
+	br := bufio.NewReaderSize(r, max(longest, 4096))
+	head, err := br.Peek(longest)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	skip := len(head) - len(stripJSONPrefix(head, prefixes))
+	if _, err := br.Discard(skip); err != nil {
+		return nil, err
+	}
+	return br, nil
+}
@@ -0,0 +1,55 @@
+package comparator
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStripJSONPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		prefixes []string
+		want     string
+	}{
+		{"byte order mark", "\xEF\xBB\xBF{\"a\":1}", nil, `{"a":1}`},
+		{"XSSI guard", ")]}',\n{\"a\":1}", []string{XSSIPrefix}, `{"a":1}`},
+		{"byte order mark and XSSI guard", "\xEF\xBB\xBF)]}',\n[1]", []string{XSSIPrefix}, `[1]`},
+		{"XSSI guard not configured", ")]}',\n{\"a\":1}", nil, ")]}',\n{\"a\":1}"},
+		{"no prefix", `{"a":1}`, []string{XSSIPrefix}, `{"a":1}`},
+		{"short input", ")]", []string{XSSIPrefix}, ")]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripJSONPrefix([]byte(tt.data), tt.prefixes)); got != tt.want {
+				t.Errorf("stripJSONPrefix() = %q, want %q", got, tt.want)
+			}
+
+			r, err := stripReaderPrefix(strings.NewReader(tt.data), tt.prefixes)
+			if err != nil {
+				t.Fatalf("stripReaderPrefix: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("reading stripped stream: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("stripReaderPrefix() read %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareIgnoresPrefixes(t *testing.T) {
+	original := []byte("\xEF\xBB\xBF{\"a\": 1}")
+	modified := []byte(")]}',\n{\"a\": 1}")
+
+	result, err := CompareWithOptions(original, modified, "a", "b", CompareOptions{StripPrefixes: []string{XSSIPrefix}})
+	if err != nil {
+		t.Fatalf("CompareWithOptions: %v", err)
+	}
+	if !result.IsJSON || result.Summary != NoChangesSummary {
+		t.Errorf("IsJSON = %v, summary = %q; want equal JSON", result.IsJSON, result.Summary)
+	}
+}
@@ -0,0 +1,69 @@
+package comparator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SectionChanges counts the leaf changes under one top-level key
+type SectionChanges struct {
+	Section string `json:"section"`
+	Changes int    `json:"changes"`
+}
+
+// groupBySection buckets leaf changes by the first segment of their path,
+// ordered by change count (descending) then name. Elements of a top-level
+// array are grouped under "[]".
+func groupBySection(changes []leafChange) []SectionChanges {
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[sectionOf(c.Path)]++
+	}
+
+	sections := make([]SectionChanges, 0, len(counts))
+	for name, n := range counts {
+		sections = append(sections, SectionChanges{Section: name, Changes: n})
+	}
+	sort.Slice(sections, func(i, j int) bool {
+		if sections[i].Changes != sections[j].Changes {
+			return sections[i].Changes > sections[j].Changes
+		}
+		return sections[i].Section < sections[j].Section
+	})
+	return sections
+}
+
+// sectionOf returns the top-level key of a leaf path
+func sectionOf(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	if path[0] == '[' {
+		return "[]"
+	}
+	if idx := strings.IndexAny(path, ".["); idx >= 0 {
+		return path[:idx]
+	}
+	return path
+}
+
+// SummarizeSections renders grouped counts, e.g. "3 changes in data, 1 in meta"
+func SummarizeSections(sections []SectionChanges) string {
+	if len(sections) == 0 {
+		return NoChangesSummary
+	}
+	parts := make([]string, len(sections))
+	for i, s := range sections {
+		if i == 0 {
+			noun := "changes"
+			if s.Changes == 1 {
+				noun = "change"
+			}
+			parts[i] = fmt.Sprintf("%d %s in %s", s.Changes, noun, s.Section)
+		} else {
+			parts[i] = fmt.Sprintf("%d in %s", s.Changes, s.Section)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
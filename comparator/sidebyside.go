@@ -0,0 +1,108 @@
+package comparator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DefaultSideBySideWidth is the total line width used when none is given
+const DefaultSideBySideWidth = 160
+
+// SideBySide renders a two-column diff of original (left) and modified
+// (right), width characters wide. The gutter marks changed lines with "|",
+// lines only on the left with "<", lines only on the right with ">" and
+// context with a blank. Only changed regions with 3 lines of context are
+// shown; longer lines are truncated with an ellipsis. JSON input is
+// pretty-printed first so both sides align line by line.
+func SideBySide(original, modified []byte, name1, name2 string, width int) string {
+	if width <= 0 {
+		width = DefaultSideBySideWidth
+	}
+	column := (width - 3) / 2
+	if column < 10 {
+		column = 10
+	}
+
+	a := splitContentLines(indentIfJSON(original))
+	b := splitContentLines(indentIfJSON(modified))
+
+	var sb strings.Builder
+	writeRow := func(left, gutter, right string) {
+		fmt.Fprintf(&sb, "%s %s %s\n", padColumn(left, column), gutter, fitColumn(right, column))
+	}
+	writeRow(name1, " ", name2)
+	sb.WriteString(strings.Repeat("-", column) + "-+-" + strings.Repeat("-", column) + "\n")
+
+	matcher := difflib.NewMatcher(a, b)
+	for i, group := range matcher.GetGroupedOpCodes(3) {
+		if i > 0 {
+			sb.WriteString(strings.Repeat(".", column) + " + " + strings.Repeat(".", column) + "\n")
+		}
+		for _, op := range group {
+			switch op.Tag {
+			case 'e':
+				for k := 0; k < op.I2-op.I1; k++ {
+					writeRow(a[op.I1+k], " ", b[op.J1+k])
+				}
+			case 'd':
+				for _, line := range a[op.I1:op.I2] {
+					writeRow(line, "<", "")
+				}
+			case 'i':
+				for _, line := range b[op.J1:op.J2] {
+					writeRow("", ">", line)
+				}
+			case 'r':
+				// Pair up replaced lines; leftovers are one-sided
+				n, m := op.I2-op.I1, op.J2-op.J1
+				for k := 0; k < n || k < m; k++ {
+					switch {
+					case k < n && k < m:
+						writeRow(a[op.I1+k], "|", b[op.J1+k])
+					case k < n:
+						writeRow(a[op.I1+k], "<", "")
+					default:
+						writeRow("", ">", b[op.J1+k])
+					}
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// splitContentLines splits content into lines without a trailing empty line
+func splitContentLines(content []byte) []string {
+	return strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+}
+
+// indentIfJSON pretty-prints valid JSON and returns anything else unchanged
+func indentIfJSON(content []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, content, "", "  "); err != nil {
+		return content
+	}
+	return buf.Bytes()
+}
+
+// fitColumn strips a carriage return and truncates a line to width runes,
+// marking truncation with an ellipsis
+func fitColumn(line string, width int) string {
+	line = strings.TrimSuffix(line, "\r")
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:width-1]) + "…"
+}
+
+// padColumn fits a line to width runes and pads it with spaces
+func padColumn(line string, width int) string {
+	line = fitColumn(line, width)
+	return line + strings.Repeat(" ", width-utf8.RuneCountInString(line))
+}
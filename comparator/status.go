@@ -0,0 +1,27 @@
+package comparator
+
+import "fmt"
+
+// StatusChange describes an HTTP status change between two responses, e.g.
+// "Status changed 200 → 404". It returns "" when the codes match or either
+// is unknown (0).
+func StatusChange(original, modified int) string {
+	if original == 0 || modified == 0 || original == modified {
+		return ""
+	}
+	return fmt.Sprintf("Status changed %d → %d", original, modified)
+}
+
+// AddStatusChange prepends a status change to the summary, so it is reported
+// even when the bodies match
+func (d *DiffResult) AddStatusChange(original, modified int) {
+	change := StatusChange(original, modified)
+	if change == "" {
+		return
+	}
+	if d.Summary == NoChangesSummary || d.Summary == "" {
+		d.Summary = change
+		return
+	}
+	d.Summary = change + ", " + d.Summary
+}
@@ -0,0 +1,182 @@
+package comparator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// maxStreamedDiffs caps how many differing elements are rendered in the text
+// diff of a streamed comparison, keeping the output bounded as well
+const maxStreamedDiffs = 20
+
+// CanStream reports whether opts allow a streaming comparison: a threshold is
+// set and no option needs whole documents
+func CanStream(opts CompareOptions) bool {
+	return opts.StreamThreshold > 0 && !opts.KeysOnly && opts.LabelPath == "" && len(opts.CardinalityPaths) == 0 &&
+		len(opts.IgnorePaths) == 0 && len(opts.ArrayKeys) == 0 && len(opts.MaskRules) == 0 && !opts.SortArrays
+}
+
+// shouldStream reports whether both inputs are top-level arrays and at least
+// one of them exceeds the streaming threshold
+func shouldStream(original, modified []byte, opts CompareOptions) bool {
+	if !CanStream(opts) {
+		return false
+	}
+	if len(original) <= opts.StreamThreshold && len(modified) <= opts.StreamThreshold {
+		return false
+	}
+	return isArrayDocument(original) && isArrayDocument(modified)
+}
+
+// isArrayDocument reports whether data starts with a JSON array
+func isArrayDocument(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// CompareArrayStreams compares two top-level JSON arrays read from original
+// and modified, as CompareWithOptions does for inputs above StreamThreshold,
+// without holding either document in memory. A byte order mark and
+// StripPrefixes are skipped. It fails if either input is not a JSON array;
+// callers can then fall back to CompareWithOptions.
+func CompareArrayStreams(original, modified io.Reader, name1, name2 string, opts CompareOptions) (*DiffResult, error) {
+	r1, err := stripReaderPrefix(original, opts.StripPrefixes)
+	if err != nil {
+		return nil, fmt.Errorf("read original: %w", err)
+	}
+	r2, err := stripReaderPrefix(modified, opts.StripPrefixes)
+	if err != nil {
+		return nil, fmt.Errorf("read modified: %w", err)
+	}
+	return compareArraysStreaming(r1, r2, name1, name2, opts.contextLines())
+}
+
+// compareArraysStreaming compares two top-level JSON arrays element by
+// element using token streaming, so only one pair of elements is decoded at a
+// time. Elements are not aligned, so the summary is the positional one of
+// positionalSummary; the text diff shows the first maxStreamedDiffs
+// differing elements and no JSON patch is built.
+func compareArraysStreaming(original, modified io.Reader, name1, name2 string, context int) (*DiffResult, error) {
+	dec1 := json.NewDecoder(original)
+	dec2 := json.NewDecoder(modified)
+	if err := expectDelim(dec1, '['); err != nil {
+		return nil, fmt.Errorf("invalid json in original: %w", err)
+	}
+	if err := expectDelim(dec2, '['); err != nil {
+		return nil, fmt.Errorf("invalid json in modified: %w", err)
+	}
+
+	result := &DiffResult{IsJSON: true, JsonPatch: []byte("[]")}
+	var text strings.Builder
+	len1, len2, changed, shown := 0, 0, 0, 0
+
+	for i := 0; ; i++ {
+		more1, more2 := dec1.More(), dec2.More()
+		if !more1 && !more2 {
+			break
+		}
+
+		var e1, e2 interface{}
+		if more1 {
+			if err := dec1.Decode(&e1); err != nil {
+				return nil, fmt.Errorf("invalid json in original: %w", err)
+			}
+			len1++
+		}
+		if more2 {
+			if err := dec2.Decode(&e2); err != nil {
+				return nil, fmt.Errorf("invalid json in modified: %w", err)
+			}
+			len2++
+		}
+
+		if more1 && more2 {
+			leaves, total := diffLeaves(e1, e2)
+			result.ChangedFields += len(leaves)
+			result.TotalFields += total
+			if deepEqual(e1, e2) {
+				continue
+			}
+			changed++
+		} else {
+			// Element only present in one array: all of its leaves changed
+			leaves := make(map[string]interface{})
+			if more1 {
+				flattenLeaves(e1, "", leaves)
+			} else {
+				flattenLeaves(e2, "", leaves)
+			}
+			result.ChangedFields += len(leaves)
+			result.TotalFields += len(leaves)
+		}
+
+		if shown < maxStreamedDiffs {
+			shown++
+			text.WriteString(elementDiff(e1, e2, more1, more2, i, name1, name2, context))
+		}
+	}
+
+	if err := expectDelim(dec1, ']'); err != nil {
+		return nil, fmt.Errorf("invalid json in original: %w", err)
+	}
+	if err := expectDelim(dec2, ']'); err != nil {
+		return nil, fmt.Errorf("invalid json in modified: %w", err)
+	}
+
+	result.Summary = positionalSummary(len1, len2, changed)
+
+	if differing := changed + abs(len1-len2); differing > shown {
+		fmt.Fprintf(&text, "... %d more differing items not shown\n", differing-shown)
+	}
+	result.TextDiff = text.String()
+	return result, nil
+}
+
+// elementDiff renders a unified diff of a single array element
+func elementDiff(e1, e2 interface{}, has1, has2 bool, index int, name1, name2 string, context int) string {
+	var lines1, lines2 []string
+	if has1 {
+		b, _ := json.MarshalIndent(e1, "", "  ")
+		lines1 = difflib.SplitLines(string(b))
+	}
+	if has2 {
+		b, _ := json.MarshalIndent(e2, "", "  ")
+		lines2 = difflib.SplitLines(string(b))
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        lines1,
+		B:        lines2,
+		FromFile: indexPath(name1, index),
+		ToFile:   indexPath(name2, index),
+		Context:  context,
+	})
+	if err != nil {
+		return fmt.Sprintf("Failed to create diff for item %d: %v\n", index, err)
+	}
+	return diff
+}
+
+// expectDelim reads the next token and checks it is the given delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected '%c', got %v", delim, tok)
+	}
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
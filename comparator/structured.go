@@ -0,0 +1,172 @@
+package comparator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StructuredDiff is a typed description of how two JSON documents differ,
+// for programs embedding the comparator. It is built from the documents
+// after every CompareOptions transform (masks, ignored paths, keys-only...),
+// so it agrees with the Summary of CompareWithOptions.
+type StructuredDiff struct {
+	// Changes lists the deepest differing paths, sorted by path. Objects and
+	// arrays present on both sides are descended; anything else that differs
+	// is a single change at its path.
+	Changes []Change `json:"changes"`
+
+	// KeyedChanges describes added, removed or changed elements of arrays
+	// matched by CompareOptions.ArrayKeys (e.g. "user id=42 changed field
+	// 'email'"). Those arrays are not descended into Changes by index.
+	KeyedChanges []string `json:"keyed_changes,omitempty"`
+
+	// Label is "path=value" for CompareOptions.LabelPath, if found
+	Label string `json:"label,omitempty"`
+
+	// ChangedFields and TotalFields count leaf fields, as in DiffResult
+	ChangedFields int `json:"changed_fields"`
+	TotalFields   int `json:"total_fields"`
+}
+
+// Change is one difference at a path
+type Change struct {
+	// Path addresses the value, e.g. "data.items[3].price"; "" is the whole document
+	Path string `json:"path"`
+
+	// Kind is ChangeAdded, ChangeRemoved, ChangeChanged, ChangeNulled or ChangeUnnulled
+	Kind string `json:"kind"`
+
+	// Old and New are the decoded values on each side (nil when the path
+	// doesn't exist on that side, or holds null)
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// HasChanges reports whether the documents differ
+func (d *StructuredDiff) HasChanges() bool {
+	return len(d.Changes) > 0 || len(d.KeyedChanges) > 0
+}
+
+// ChangeNode is a node of the tree returned by StructuredDiff.Tree. Each
+// node is one path segment; nodes where a change occurred carry it.
+type ChangeNode struct {
+	Segment  string        `json:"segment"` // Object key, or "[N]" for an array index
+	Path     string        `json:"path"`
+	Change   *Change       `json:"change,omitempty"`
+	Children []*ChangeNode `json:"children,omitempty"`
+}
+
+// Tree arranges Changes by path, so callers can walk them section by
+// section. The root node has an empty path; a change to the whole document
+// is attached to it.
+func (d *StructuredDiff) Tree() *ChangeNode {
+	root := &ChangeNode{}
+	for i := range d.Changes {
+		c := &d.Changes[i]
+		node := root
+		for _, seg := range splitChangePath(c.Path) {
+			node = node.child(seg)
+		}
+		node.Change = c
+	}
+	return root
+}
+
+// child returns the child for a segment, creating it if needed
+func (n *ChangeNode) child(segment string) *ChangeNode {
+	for _, c := range n.Children {
+		if c.Segment == segment {
+			return c
+		}
+	}
+	path := segment
+	if strings.HasPrefix(segment, "[") {
+		path = n.Path + segment
+	} else if n.Path != "" {
+		path = n.Path + "." + segment
+	}
+	c := &ChangeNode{Segment: segment, Path: path}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// splitChangePath splits a concrete path such as "a.b[2].c" into "a", "b",
+// "[2]", "c"
+func splitChangePath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(path, ".") {
+		key, rest := part, ""
+		if idx := strings.Index(part, "["); idx >= 0 {
+			key, rest = part[:idx], part[idx:]
+		}
+		if key != "" {
+			out = append(out, key)
+		}
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				out = append(out, rest)
+				break
+			}
+			out = append(out, rest[:end+1])
+			rest = rest[end+1:]
+		}
+	}
+	return out
+}
+
+// CompareStructured compares two JSON documents and returns the differences
+// as typed changes instead of text. Both inputs must be JSON; use
+// CompareWithOptions for text or NDJSON content. StreamThreshold and
+// GroupBySection have no effect.
+func CompareStructured(original, modified []byte, opts CompareOptions) (*StructuredDiff, error) {
+	if !isValidJSON(stripJSONPrefix(original, opts.StripPrefixes)) {
+		return nil, fmt.Errorf("original is not valid JSON")
+	}
+	if !isValidJSON(stripJSONPrefix(modified, opts.StripPrefixes)) {
+		return nil, fmt.Errorf("modified is not valid JSON")
+	}
+
+	p, err := prepareJSON(original, modified, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &StructuredDiff{Changes: []Change{}, Label: p.label}
+	if !opts.KeysOnly {
+		diff.KeyedChanges = p.keyedChanges
+	}
+	collectChanges(p.v1, p.v2, "", 0, p.keyedPaths, func(path, kind string, old, new interface{}) {
+		diff.Changes = append(diff.Changes, Change{Path: path, Kind: kind, Old: old, New: new})
+	})
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return lessPath(diff.Changes[i].Path, diff.Changes[j].Path)
+	})
+
+	leafChanges, total := diffLeaves(p.v1, p.v2)
+	diff.ChangedFields, diff.TotalFields = len(leafChanges), total
+	return diff, nil
+}
+
+// lessPath orders paths segment by segment, comparing array indexes
+// numerically so "a[2]" sorts before "a[10]"
+func lessPath(a, b string) bool {
+	sa, sb := splitChangePath(a), splitChangePath(b)
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		if sa[i] == sb[i] {
+			continue
+		}
+		na, errA := strconv.Atoi(strings.Trim(sa[i], "[]"))
+		nb, errB := strconv.Atoi(strings.Trim(sb[i], "[]"))
+		if errA == nil && errB == nil && strings.HasPrefix(sa[i], "[") && strings.HasPrefix(sb[i], "[") {
+			return na < nb
+		}
+		return sa[i] < sb[i]
+	}
+	return len(sa) < len(sb)
+}
@@ -3,15 +3,37 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/executor"
 )
 
 // DefaultTimeout is the default timeout for command execution
 const DefaultTimeout = 30 * time.Second
 
+// Execution engines
+const (
+	EngineCurl   = "curl"   // Run commands with the curl binary (default)
+	EngineNative = "native" // Parse curl commands and send them with net/http
+)
+
+// Comparison modes
+const (
+	CompareModePairwise = "pairwise" // Diff each version pair (default)
+	CompareModeMulti    = "multi"    // Also compare all versions field by field in one table
+)
+
+// TestingEnvVar must be set to "1" for test-only options (inject_delay_ms,
+// inject_jitter_ms) to take effect, so they can't slip into production runs
+const TestingEnvVar = "API_DIFF_CHECKER_TESTING"
+
 // TestCase represents a single test case row in the matrix
 // Each test case can have different curl commands per version
 type TestCase struct {
@@ -21,14 +43,198 @@ type TestCase struct {
 	// Commands maps version name to the curl command for that version
 	// Example: {"v1": "curl {{BASE_URL}}/users", "v2": "curl {{BASE_URL}}/customers"}
 	Commands map[string]string `json:"commands"`
+
+	// LabelPath is an optional response path (e.g. "order.id") whose value is
+	// prefixed to each change in the diff summary, e.g. "order.id=12345: ..."
+	LabelPath string `json:"label_path,omitempty"`
+
+	// Body is a JSON request body declared once for all versions. It replaces
+	// {{BODY}} in each command, or is appended as --data-raw if absent.
+	Body json.RawMessage `json:"body,omitempty"`
+
+	// BodyRenames maps version -> (field path -> new field name), adapting the
+	// shared Body per version, e.g. {"v2": {"userId": "user_id"}}
+	BodyRenames map[string]map[string]string `json:"body_renames,omitempty"`
+
+	// CardinalityPaths lists response paths (e.g. "items[].category") whose
+	// sets of distinct values are compared across versions, catching dropped
+	// or new categories regardless of element order
+	CardinalityPaths []string `json:"cardinality_paths,omitempty"`
+
+	// Success maps version -> criteria the response must meet on its own,
+	// independent of the cross-version diff. The key "*" applies to every
+	// version without its own entry.
+	Success map[string]SuccessCriteria `json:"success,omitempty"`
+
+	// KeysOnly overrides Config.KeysOnly for this test case; nil inherits it
+	KeysOnly *bool `json:"keys_only,omitempty"`
+
+	// Expect asserts what every version's response must look like
+	Expect *Expectation `json:"expect,omitempty"`
+
+	// Tags group test cases (e.g. "smoke", "auth") for selection with a Filter
+	Tags []string `json:"tags,omitempty"`
+
+	// Schema maps version -> JSON Schema the response is compared against,
+	// reporting undocumented and missing fields. A reference may select an
+	// OpenAPI component, e.g. "openapi.json#/components/schemas/User".
+	// The key "*" applies to every version without its own entry.
+	Schema map[string]string `json:"schema,omitempty"`
+}
+
+// SchemaFor returns the schema reference that applies to a version
+func (tc TestCase) SchemaFor(version string) (string, bool) {
+	if ref, ok := tc.Schema[version]; ok {
+		return ref, true
+	}
+	ref, ok := tc.Schema["*"]
+	return ref, ok
+}
+
+// ResolvePath resolves a path from the config against the config file's
+// directory (relative paths are left as is for configs without one)
+func (c *Config) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || c.BaseDir == "" {
+		return path
+	}
+	return filepath.Join(c.BaseDir, path)
+}
+
+// Expectation is a contract check applied to each version's response
+type Expectation struct {
+	// Status is the expected HTTP status code (0 = not checked)
+	Status int `json:"status,omitempty"`
+
+	// BodyContains lists substrings the response body must contain
+	BodyContains []string `json:"body_contains,omitempty"`
+}
+
+// KeysOnlyFor returns the effective keys-only setting of a test case
+func (tc TestCase) KeysOnlyFor(cfg *Config) bool {
+	if tc.KeysOnly != nil {
+		return *tc.KeysOnly
+	}
+	return cfg.KeysOnly
+}
+
+// SuccessCriteria declares what a successful response looks like.
+// All configured checks must pass.
+type SuccessCriteria struct {
+	// Status lists the accepted HTTP status codes
+	Status []int `json:"status,omitempty"`
+
+	// ExpectJSON requires the body to be valid JSON
+	ExpectJSON bool `json:"expect_json,omitempty"`
+
+	// NonEmpty lists response paths (e.g. "items", "data.user.id") that must
+	// exist and not be null, an empty string, array or object
+	NonEmpty []string `json:"non_empty,omitempty"`
+
+	// Schema is the path to a JSON Schema file the body must satisfy
+	Schema string `json:"schema,omitempty"`
+}
+
+// SuccessFor returns the success criteria that apply to a version
+func (tc TestCase) SuccessFor(version string) (SuccessCriteria, bool) {
+	if criteria, ok := tc.Success[version]; ok {
+		return criteria, true
+	}
+	criteria, ok := tc.Success["*"]
+	return criteria, ok
+}
+
+// ExpectedDiff acknowledges intentional changes in a test case's responses
+type ExpectedDiff struct {
+	// TestCase names the test case ("" or "*" = every test case)
+	TestCase string `json:"test_case,omitempty"`
+
+	// Paths lists changed response paths (e.g. "data.price",
+	// "items[].label") that are expected; changes beneath a path count too
+	Paths []string `json:"paths"`
+
+	// Reason explains why the change is expected, shown alongside it
+	Reason string `json:"reason,omitempty"`
+}
+
+// Normalizer is an external command that rewrites every response before
+// comparison. It receives the body on stdin and must print JSON on stdout.
+type Normalizer struct {
+	Command string `json:"command"`
+	Timeout int    `json:"timeout,omitempty"` // Seconds (default: 10)
+}
+
+// GetTimeout returns the configured normalizer timeout or the default
+func (n *Normalizer) GetTimeout() time.Duration {
+	if n.Timeout <= 0 {
+		return executor.DefaultFilterTimeout
+	}
+	return time.Duration(n.Timeout) * time.Second
+}
+
+// Webhook payload formats
+const (
+	NotifyFormatJSON  = "json"  // Structured summary (default)
+	NotifyFormatSlack = "slack" // Slack incoming-webhook message
+)
+
+// NotifyOptions posts a summary of the run to a webhook (e.g. a Slack
+// incoming webhook) once it finishes
+type NotifyOptions struct {
+	WebhookURL string `json:"webhook_url"`
+
+	// OnlyOnDiff skips the notification when every version pair matched
+	// and nothing failed
+	OnlyOnDiff bool `json:"only_on_diff,omitempty"`
+
+	// Format is "json" (default) or "slack"
+	Format string `json:"format,omitempty"`
+
+	// Timeout in seconds for delivering the notification (default: 10)
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// DefaultNotifyTimeout bounds webhook delivery so a hung endpoint can't
+// keep the program from exiting
+const DefaultNotifyTimeout = 10 * time.Second
+
+// GetTimeout returns the configured webhook timeout or the default
+func (n *NotifyOptions) GetTimeout() time.Duration {
+	if n.Timeout <= 0 {
+		return DefaultNotifyTimeout
+	}
+	return time.Duration(n.Timeout) * time.Second
+}
+
+// HTTPOptions controls how requests are sent, in both curl and native mode
+type HTTPOptions struct {
+	// FollowRedirects follows 3xx redirects; curl commands get -L unless they
+	// already have it. Off by default, matching curl.
+	FollowRedirects bool `json:"follow_redirects,omitempty"`
+
+	// MaxRedirects fails a request that redirects more than this many times
+	// (curl --max-redirs; 0 = curl/net/http default)
+	MaxRedirects int `json:"max_redirects,omitempty"`
 }
 
 // Config represents the users input configuration
 type Config struct {
+	// BaseDir is the directory of the loaded config file, against which
+	// "@file" request bodies are resolved. Set by Load; empty otherwise.
+	BaseDir string `json:"-"`
+
+	// Include lists config files merged before this one, relative to it.
+	// Later files override earlier keys; versions merge and test_cases
+	// concatenate. Only supported by Load.
+	Include []string `json:"include,omitempty"`
+
 	// Versions maps a version name to its base URL
 	// Example: "v1" -> "http://localhost:9876", "v2" -> "http://localhost:9090"
 	Versions map[string]string `json:"versions"`
 
+	// Baseline names a version (e.g. production) that every other version is
+	// compared against, instead of comparing adjacent versions
+	Baseline string `json:"baseline,omitempty"`
+
 	// Commands is a list of raw curl commands to execute (LEGACY - for backward compatibility)
 	// Users should use the placeholder {{BASE_URL}} in these commands
 	// which will be replaced by the specific version's URL.
@@ -38,11 +244,148 @@ type Config struct {
 	// TestCases is the new matrix format where each row can have different commands per version
 	TestCases []TestCase `json:"test_cases,omitempty"`
 
+	// Filter restricts the run to some test cases by name or tag
+	Filter *Filter `json:"filter,omitempty"`
+
 	// KeysOnly if true, compares only JSON structure (keys), not values
 	KeysOnly bool `json:"keys_only,omitempty"`
 
+	// GroupBySection additionally summarizes changes per top-level key of
+	// the response (e.g. "3 changes in data, 1 in meta")
+	GroupBySection bool `json:"group_by_section,omitempty"`
+
+	// ArrayKeys maps an array path (e.g. "data.users", "$" for a top-level
+	// array) to the field identifying its elements (e.g. "id"), so elements
+	// are matched by identity rather than position
+	ArrayKeys map[string]string `json:"array_keys,omitempty"`
+
+	// Canonicalize diffs responses with sorted object keys, so key order
+	// doesn't produce text diff churn; SortArrays also sorts arrays of scalars
+	Canonicalize bool `json:"canonicalize,omitempty"`
+	SortArrays   bool `json:"sort_arrays,omitempty"`
+
+	// CompareMode "multi" adds a field-by-field comparison of every version
+	// of a test case to the pairwise diffs, which still decide the outcome
+	CompareMode string `json:"compare_mode,omitempty"`
+
+	// ContextLines is how many unchanged lines text diffs show around each
+	// change; nil uses the default of 3 and 0 shows only the changes
+	ContextLines *int `json:"context_lines,omitempty"`
+
+	// MaskRules replace unpredictable string values (UUIDs, signed URLs)
+	// matched by a path glob and regex with a canonical token before comparison
+	MaskRules []comparator.MaskRule `json:"mask_rules,omitempty"`
+
+	// IgnorePaths lists response paths (e.g. "data.requestId",
+	// "items[].createdAt") excluded from comparison
+	IgnorePaths []string `json:"ignore_paths,omitempty"`
+
+	// IgnoreFile names a file of further ignore paths, one per line with #
+	// comments, relative to the config file. They are appended to
+	// IgnorePaths when the config is loaded.
+	IgnoreFile string `json:"ignore_file,omitempty"`
+
+	// StripPrefixes lists guards such as the XSSI prefix ")]}',\n" removed
+	// from the start of responses before they are parsed as JSON
+	StripPrefixes []string `json:"strip_prefixes,omitempty"`
+
+	// ExpectedDiffs acknowledges intentional changes: they are still shown,
+	// labeled expected, but don't count as differences
+	ExpectedDiffs []ExpectedDiff `json:"expected_diffs,omitempty"`
+
 	// Timeout specifies command execution timeout in seconds (default: 30)
 	Timeout int `json:"timeout,omitempty"`
+
+	// Engine selects how commands run: "curl" (default) or "native", which
+	// sends curl-style commands with net/http and needs no curl binary
+	Engine string `json:"engine,omitempty"`
+
+	// HTTP holds request options such as redirect handling
+	HTTP *HTTPOptions `json:"http,omitempty"`
+
+	// Proxy routes every request through an HTTP(S) or SOCKS proxy, e.g.
+	// "http://proxy.corp:3128". Proxies overrides it per version. Without
+	// either, the standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY variables apply.
+	Proxy   string            `json:"proxy,omitempty"`
+	Proxies map[string]string `json:"proxies,omitempty"`
+
+	// InsecureSkipVerify disables TLS certificate verification (curl -k) for
+	// self-signed staging endpoints. InsecureSkipVerifyFor overrides it per
+	// version. Off by default; every run that uses it logs a warning.
+	InsecureSkipVerify    bool            `json:"insecure_skip_verify,omitempty"`
+	InsecureSkipVerifyFor map[string]bool `json:"insecure_skip_verify_for,omitempty"`
+
+	// CompareRequests if true, also diffs the resolved requests (method, URL,
+	// query, headers, body) of each version pair alongside the responses
+	CompareRequests bool `json:"compare_requests,omitempty"`
+
+	// CompareTrailers if true, diffs HTTP trailers of each version pair when
+	// both executions captured them
+	CompareTrailers bool `json:"compare_trailers,omitempty"`
+
+	// CompareHeaders if true, captures response headers and diffs them for
+	// each version pair. IgnoreHeaders lists volatile names (e.g. "Date")
+	// to leave out, matched case-insensitively.
+	CompareHeaders bool     `json:"compare_headers,omitempty"`
+	IgnoreHeaders  []string `json:"ignore_headers,omitempty"`
+
+	// MaxChangedFieldsPercent fails the run only when more than this
+	// percentage of leaf fields changed in a version pair (0 = disabled)
+	MaxChangedFieldsPercent float64 `json:"max_changed_fields_percent,omitempty"`
+
+	// FailFast stops the run after the first test case with a difference or
+	// an error; the remaining test cases are not executed
+	FailFast bool `json:"fail_fast,omitempty"`
+
+	// MaxConcurrency caps how many commands run at once across the whole run,
+	// so many versions don't hit the target servers simultaneously
+	// (0 = unlimited)
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// ParallelTestCases runs up to this many test cases at once (0 or 1 =
+	// one after another). Executions still share the MaxConcurrency limit.
+	ParallelTestCases int `json:"parallel_test_cases,omitempty"`
+
+	// MaxResponseBytes caps how much of each response body is kept; larger
+	// responses are truncated and their diffs flagged as size-limited
+	// (0 = executor.DefaultMaxResponseBytes, 64MB)
+	MaxResponseBytes int64 `json:"max_response_bytes,omitempty"`
+
+	// Repeat runs each version's command this many times and reports the
+	// fields that differ between runs of the same version, i.e. inherent
+	// nondeterminism rather than cross-version drift (0 or 1 = run once)
+	Repeat int `json:"repeat,omitempty"`
+
+	// StreamThresholdBytes compares top-level JSON arrays element by element
+	// when a response is larger than this many bytes, bounding memory use
+	// (0 = disabled)
+	StreamThresholdBytes int `json:"stream_threshold_bytes,omitempty"`
+
+	// Normalizer, if set, is applied to every response of every version
+	// before comparison, so all versions are treated identically
+	Normalizer *Normalizer `json:"normalizer,omitempty"`
+
+	// Variables maps version -> placeholder name -> value, substituted for
+	// {{NAME}} tokens in that version's commands (e.g. tokens, tenant IDs)
+	Variables map[string]map[string]string `json:"variables,omitempty"`
+
+	// GlobalVars holds placeholder values shared by all versions; values in
+	// Variables take precedence
+	GlobalVars map[string]string `json:"global_vars,omitempty"`
+
+	// DefaultHeaders are request headers added to every command (e.g. a
+	// shared auth token); Headers maps version -> headers overriding them.
+	// A header the command already sets is left alone.
+	DefaultHeaders map[string]string            `json:"default_headers,omitempty"`
+	Headers        map[string]map[string]string `json:"headers,omitempty"`
+
+	// Notify sends a run summary to a webhook after CLI runs
+	Notify *NotifyOptions `json:"notify,omitempty"`
+
+	// InjectDelayMs and InjectJitterMs add an artificial delay before each
+	// command (test-only, requires API_DIFF_CHECKER_TESTING=1)
+	InjectDelayMs  int `json:"inject_delay_ms,omitempty"`
+	InjectJitterMs int `json:"inject_jitter_ms,omitempty"`
 }
 
 // ValidationError represents a validation error with details
@@ -82,6 +425,21 @@ func (v *ValidationResult) Error() string {
 func (c *Config) Validate() *ValidationResult {
 	result := &ValidationResult{}
 
+	// Includes and ignore files are read by Load; anywhere else (e.g. a
+	// config posted to the web server) they would be silently dropped
+	if len(c.Include) > 0 && c.BaseDir == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "include",
+			Message: "include is only supported when loading a config file",
+		})
+	}
+	if c.IgnoreFile != "" && c.BaseDir == "" {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "ignore_file",
+			Message: "ignore_file is only supported when loading a config file",
+		})
+	}
+
 	// Check versions
 	if len(c.Versions) == 0 {
 		result.Errors = append(result.Errors, ValidationError{
@@ -136,6 +494,14 @@ func (c *Config) Validate() *ValidationResult {
 		}
 	}
 
+	// Check baseline refers to a known version
+	if _, ok := c.Versions[c.Baseline]; c.Baseline != "" && !ok {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "baseline",
+			Message: fmt.Sprintf("version '%s' is not defined in versions", c.Baseline),
+		})
+	}
+
 	// Check test cases (new format) or commands (legacy format)
 	if len(c.TestCases) > 0 {
 		// Validate test cases
@@ -170,6 +536,53 @@ func (c *Config) Validate() *ValidationResult {
 						fmt.Sprintf("test_cases[%d]: no commands contain {{BASE_URL}} placeholder", i))
 				}
 			}
+
+			if len(tc.BodyRenames) > 0 && len(tc.Body) == 0 {
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("test_cases[%d]: body_renames has no effect without body", i))
+			}
+			for version := range tc.BodyRenames {
+				if _, ok := tc.Commands[version]; !ok {
+					result.Warnings = append(result.Warnings,
+						fmt.Sprintf("test_cases[%d].body_renames: version '%s' has no command", i, version))
+				}
+			}
+
+			for version, criteria := range tc.Success {
+				if _, ok := tc.Commands[version]; !ok && version != "*" {
+					result.Warnings = append(result.Warnings,
+						fmt.Sprintf("test_cases[%d].success: version '%s' has no command", i, version))
+				}
+				for _, code := range criteria.Status {
+					if code < 100 || code > 599 {
+						result.Errors = append(result.Errors, ValidationError{
+							Field:   fmt.Sprintf("test_cases[%d].success[%s].status", i, version),
+							Message: fmt.Sprintf("invalid HTTP status code %d", code),
+						})
+					}
+				}
+			}
+
+			for version, ref := range tc.Schema {
+				if _, ok := tc.Commands[version]; !ok && version != "*" {
+					result.Warnings = append(result.Warnings,
+						fmt.Sprintf("test_cases[%d].schema: version '%s' has no command", i, version))
+				}
+				file, _, _ := strings.Cut(c.ResolvePath(ref), "#")
+				if _, err := os.Stat(file); err != nil {
+					result.Errors = append(result.Errors, ValidationError{
+						Field:   fmt.Sprintf("test_cases[%d].schema[%s]", i, version),
+						Message: fmt.Sprintf("schema file not found: %s", file),
+					})
+				}
+			}
+
+			if tc.Expect != nil && tc.Expect.Status != 0 && (tc.Expect.Status < 100 || tc.Expect.Status > 599) {
+				result.Errors = append(result.Errors, ValidationError{
+					Field:   fmt.Sprintf("test_cases[%d].expect.status", i),
+					Message: fmt.Sprintf("invalid HTTP status code %d", tc.Expect.Status),
+				})
+			}
 		}
 	} else if len(c.Commands) == 0 {
 		// No test cases and no legacy commands
@@ -201,6 +614,66 @@ func (c *Config) Validate() *ValidationResult {
 		}
 	}
 
+	// Warn about placeholders without a binding for a version they run under
+	for _, tc := range c.GetTestCases() {
+		versions := make([]string, 0, len(tc.Commands))
+		for version := range tc.Commands {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+		for _, version := range versions {
+			vars := c.VariablesFor(version)
+			for _, name := range executor.Placeholders(tc.Commands[version]) {
+				if _, ok := vars[name]; !ok {
+					warning := fmt.Sprintf("%s: placeholder {{%s}} has no value for version '%s'", tc.Name, name, version)
+					if suggestion := executor.SuggestPlaceholder(name, vars); suggestion != "" {
+						warning += fmt.Sprintf(" (did you mean {{%s}}?)", suggestion)
+					}
+					result.Warnings = append(result.Warnings, warning)
+				}
+			}
+		}
+	}
+
+	// Validate injected headers, warning about placeholders without a value
+	for version := range c.Headers {
+		if _, ok := c.Versions[version]; !ok {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("headers.%s", version),
+				Message: fmt.Sprintf("version '%s' is not defined in versions", version),
+			})
+		}
+	}
+	versionNames := make([]string, 0, len(c.Versions))
+	for version := range c.Versions {
+		versionNames = append(versionNames, version)
+	}
+	sort.Strings(versionNames)
+	for _, version := range versionNames {
+		headers := c.HeadersFor(version)
+		vars := c.VariablesFor(version)
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if strings.TrimSpace(name) == "" || strings.ContainsAny(name, ": \t\r\n") {
+				result.Errors = append(result.Errors, ValidationError{
+					Field:   "default_headers/headers",
+					Message: fmt.Sprintf("invalid header name %q", name),
+				})
+				continue
+			}
+			for _, placeholder := range executor.Placeholders(headers[name]) {
+				if _, ok := vars[placeholder]; !ok {
+					result.Warnings = append(result.Warnings,
+						fmt.Sprintf("header %s: placeholder {{%s}} has no value for version '%s'", name, placeholder, version))
+				}
+			}
+		}
+	}
+
 	// Validate timeout
 	if c.Timeout < 0 {
 		result.Errors = append(result.Errors, ValidationError{
@@ -209,6 +682,230 @@ func (c *Config) Validate() *ValidationResult {
 		})
 	}
 
+	// Validate execution engine
+	if c.Engine != "" && c.Engine != EngineCurl && c.Engine != EngineNative {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "engine",
+			Message: fmt.Sprintf("must be %q or %q", EngineCurl, EngineNative),
+		})
+	}
+
+	// Validate comparison mode
+	if c.CompareMode != "" && c.CompareMode != CompareModePairwise && c.CompareMode != CompareModeMulti {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "compare_mode",
+			Message: fmt.Sprintf("must be %q or %q", CompareModePairwise, CompareModeMulti),
+		})
+	}
+
+	// Validate ignored paths
+	for i, path := range c.IgnorePaths {
+		if err := comparator.ValidateIgnorePath(path); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("ignore_paths[%d]", i),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	// Validate prefixes to strip
+	for i, prefix := range c.StripPrefixes {
+		if prefix == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("strip_prefixes[%d]", i),
+				Message: "prefix cannot be empty",
+			})
+		}
+	}
+
+	// Validate expected diffs
+	for i, expected := range c.ExpectedDiffs {
+		field := fmt.Sprintf("expected_diffs[%d]", i)
+		if len(expected.Paths) == 0 {
+			result.Errors = append(result.Errors, ValidationError{Field: field, Message: "paths cannot be empty"})
+		}
+		for j, path := range expected.Paths {
+			if err := comparator.ValidatePath(path); err != nil {
+				result.Errors = append(result.Errors, ValidationError{
+					Field:   fmt.Sprintf("%s.paths[%d]", field, j),
+					Message: err.Error(),
+				})
+			}
+		}
+		if expected.TestCase != "" && expected.TestCase != "*" && !c.hasTestCase(expected.TestCase) {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("%s: test case '%s' is not defined", field, expected.TestCase))
+		}
+	}
+
+	// Validate mask rules
+	for i, rule := range c.MaskRules {
+		if err := comparator.ValidateMaskRule(rule); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("mask_rules[%d]", i),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	// Validate keyed array paths
+	for path, key := range c.ArrayKeys {
+		if path != comparator.RootPath {
+			if err := comparator.ValidatePath(path); err != nil {
+				result.Errors = append(result.Errors, ValidationError{
+					Field:   fmt.Sprintf("array_keys[%s]", path),
+					Message: err.Error(),
+				})
+			}
+		}
+		if strings.TrimSpace(key) == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("array_keys[%s]", path),
+				Message: "key field cannot be empty",
+			})
+		}
+	}
+
+	// Validate normalizer
+	if c.Normalizer != nil {
+		if strings.TrimSpace(c.Normalizer.Command) == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "normalizer.command",
+				Message: "command cannot be empty",
+			})
+		}
+		if c.Normalizer.Timeout < 0 {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "normalizer.timeout",
+				Message: "timeout cannot be negative",
+			})
+		}
+	}
+
+	// Validate concurrency limit
+	if c.MaxConcurrency < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "max_concurrency",
+			Message: "cannot be negative",
+		})
+	}
+	if c.ParallelTestCases < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "parallel_test_cases",
+			Message: "cannot be negative",
+		})
+	}
+
+	// Validate HTTP options
+	if c.HTTP != nil && c.HTTP.MaxRedirects < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "http.max_redirects",
+			Message: "cannot be negative",
+		})
+	}
+
+	// Validate proxies
+	if c.Proxy != "" {
+		if err := validateProxy(c.Proxy); err != nil {
+			result.Errors = append(result.Errors, ValidationError{Field: "proxy", Message: err.Error()})
+		}
+	}
+	for version, proxy := range c.Proxies {
+		field := fmt.Sprintf("proxies.%s", version)
+		if _, ok := c.Versions[version]; !ok {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("version '%s' is not defined in versions", version),
+			})
+		} else if err := validateProxy(proxy); err != nil {
+			result.Errors = append(result.Errors, ValidationError{Field: field, Message: err.Error()})
+		}
+	}
+
+	// Validate per-version TLS overrides
+	for version := range c.InsecureSkipVerifyFor {
+		if _, ok := c.Versions[version]; !ok {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("insecure_skip_verify_for.%s", version),
+				Message: fmt.Sprintf("version '%s' is not defined in versions", version),
+			})
+		}
+	}
+
+	// Validate the test case filter
+	if _, err := c.SelectedTestCases(); err != nil {
+		result.Errors = append(result.Errors, ValidationError{Field: "filter", Message: err.Error()})
+	}
+
+	// Validate response size limit
+	if c.MaxResponseBytes < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "max_response_bytes",
+			Message: "cannot be negative",
+		})
+	}
+	if c.Repeat < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "repeat",
+			Message: "cannot be negative",
+		})
+	}
+	if c.ContextLines != nil && *c.ContextLines < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "context_lines",
+			Message: "cannot be negative",
+		})
+	}
+
+	// Validate streaming threshold
+	if c.StreamThresholdBytes < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "stream_threshold_bytes",
+			Message: "cannot be negative",
+		})
+	}
+
+	// Validate notification webhook
+	if c.Notify != nil {
+		if u, err := url.Parse(c.Notify.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "notify.webhook_url",
+				Message: "must be an http or https URL",
+			})
+		}
+		if c.Notify.Format != "" && c.Notify.Format != NotifyFormatJSON && c.Notify.Format != NotifyFormatSlack {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "notify.format",
+				Message: fmt.Sprintf("must be %q or %q", NotifyFormatJSON, NotifyFormatSlack),
+			})
+		}
+		if c.Notify.Timeout < 0 {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "notify.timeout",
+				Message: "timeout cannot be negative",
+			})
+		}
+	}
+
+	// Validate test-only delay injection
+	if c.InjectDelayMs < 0 || c.InjectJitterMs < 0 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "inject_delay_ms/inject_jitter_ms",
+			Message: "cannot be negative",
+		})
+	} else if (c.InjectDelayMs > 0 || c.InjectJitterMs > 0) && !testingEnabled() {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("inject_delay_ms/inject_jitter_ms are ignored unless %s=1", TestingEnvVar))
+	}
+
+	// Validate changed fields threshold
+	if c.MaxChangedFieldsPercent < 0 || c.MaxChangedFieldsPercent > 100 {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "max_changed_fields_percent",
+			Message: "must be between 0 and 100",
+		})
+	}
+
 	return result
 }
 
@@ -220,6 +917,103 @@ func (c *Config) GetTimeout() time.Duration {
 	return time.Duration(c.Timeout) * time.Second
 }
 
+// GetInjectedDelay returns the test-only delay and jitter to apply before each
+// command. Both are zero unless testing mode is enabled via TestingEnvVar.
+func (c *Config) GetInjectedDelay() (time.Duration, time.Duration) {
+	if !testingEnabled() {
+		return 0, 0
+	}
+	return time.Duration(c.InjectDelayMs) * time.Millisecond, time.Duration(c.InjectJitterMs) * time.Millisecond
+}
+
+// VariablesFor returns the placeholder values for a version: GlobalVars
+// overridden by the version's own Variables
+func (c *Config) VariablesFor(version string) map[string]string {
+	vars := make(map[string]string, len(c.GlobalVars)+len(c.Variables[version]))
+	for name, value := range c.GlobalVars {
+		vars[name] = value
+	}
+	for name, value := range c.Variables[version] {
+		vars[name] = value
+	}
+	return vars
+}
+
+// HeadersFor returns the request headers injected into a version's
+// commands: DefaultHeaders overridden by the version's Headers entry, with
+// names compared case-insensitively
+func (c *Config) HeadersFor(version string) map[string]string {
+	headers := make(map[string]string, len(c.DefaultHeaders)+len(c.Headers[version]))
+	for name, value := range c.DefaultHeaders {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	for name, value := range c.Headers[version] {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	return headers
+}
+
+// ProxyFor returns the proxy URL for a version: its entry in Proxies, else
+// Proxy. Empty means the proxy environment variables apply.
+func (c *Config) ProxyFor(version string) string {
+	if proxy, ok := c.Proxies[version]; ok {
+		return proxy
+	}
+	return c.Proxy
+}
+
+// InsecureFor reports whether TLS verification is skipped for a version:
+// its entry in InsecureSkipVerifyFor, else InsecureSkipVerify
+func (c *Config) InsecureFor(version string) bool {
+	if insecure, ok := c.InsecureSkipVerifyFor[version]; ok {
+		return insecure
+	}
+	return c.InsecureSkipVerify
+}
+
+// ExpectedDiffsFor returns the expected diffs that apply to a test case
+func (c *Config) ExpectedDiffsFor(testCase string) []ExpectedDiff {
+	var expected []ExpectedDiff
+	for _, e := range c.ExpectedDiffs {
+		if e.TestCase == "" || e.TestCase == "*" || e.TestCase == testCase {
+			expected = append(expected, e)
+		}
+	}
+	return expected
+}
+
+// hasTestCase reports whether a test case with the given name is defined
+func (c *Config) hasTestCase(name string) bool {
+	for _, tc := range c.GetTestCases() {
+		if tc.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateProxy checks a proxy is an absolute URL with a supported scheme
+func validateProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("proxy must be an http, https, socks5 or socks5h URL with a host, got %q", proxy)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("proxy must be an http, https, socks5 or socks5h URL with a host, got %q", proxy)
+	}
+	return nil
+}
+
+// testingEnabled reports whether test-only options are allowed
+func testingEnabled() bool {
+	return os.Getenv(TestingEnvVar) == "1"
+}
+
 // GetTestCases returns normalized test cases.
 // If TestCases is provided, returns it directly.
 // If only legacy Commands are provided, converts them to test cases
@@ -248,15 +1042,40 @@ func (c *Config) GetTestCases() []TestCase {
 
 // Load reads a config file from path and validates it
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	return LoadWithOptions(path, LoadOptions{})
+}
+
+// LoadOptions configures LoadWithOptions
+type LoadOptions struct {
+	// SkipValidation returns the parsed config without validating it, for
+	// callers that run and report Validate themselves
+	SkipValidation bool
+}
+
+// LoadWithOptions is Load with options
+func LoadWithOptions(path string, opts LoadOptions) (*Config, error) {
+	doc, err := readWithIncludes(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, fmt.Errorf("failed to merge config: %w", err)
 	}
 
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config JSON: %w", err)
 	}
+	if abs, err := filepath.Abs(path); err == nil {
+		cfg.BaseDir = filepath.Dir(abs)
+	}
+	if err := cfg.loadIgnoreFile(); err != nil {
+		return nil, err
+	}
+	if opts.SkipValidation {
+		return &cfg, nil
+	}
 
 	// Validate configuration
 	validation := cfg.Validate()
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// inlineConfig decodes a config the way the web server does, without a file
+func inlineConfig(t *testing.T, data string) *Config {
+	t.Helper()
+	var cfg Config
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("decode config: %v", err)
+	}
+	return &cfg
+}
+
+func TestValidateRejectsIncludeWithoutFile(t *testing.T) {
+	cfg := inlineConfig(t, `{"include": ["base.json"], "versions": {"v1": "http://a"}, "commands": ["curl {{BASE_URL}}"]}`)
+
+	validation := cfg.Validate()
+	if validation.IsValid() || !strings.Contains(validation.Error(), "include") {
+		t.Errorf("Validate() = %v, want an include error", validation.Error())
+	}
+
+	cfg.BaseDir = t.TempDir()
+	for _, e := range cfg.Validate().Errors {
+		if e.Field == "include" {
+			t.Errorf("include rejected for a config loaded from a file: %v", e)
+		}
+	}
+}
+
+func TestValidateRejectsIgnoreFileWithoutFile(t *testing.T) {
+	cfg := inlineConfig(t, `{"ignore_file": "ignore.txt", "versions": {"v1": "http://a"}, "commands": ["curl {{BASE_URL}}"]}`)
+
+	validation := cfg.Validate()
+	if validation.IsValid() || !strings.Contains(validation.Error(), "ignore_file") {
+		t.Errorf("Validate() = %v, want an ignore_file error", validation.Error())
+	}
+	if _, err := LoadFromJSON([]byte(`{"ignore_file": "ignore.txt", "versions": {"v1": "http://a"}, "commands": ["curl {{BASE_URL}}"]}`)); err == nil {
+		t.Error("LoadFromJSON accepted ignore_file")
+	}
+}
+
+func TestKeysOnlyFor(t *testing.T) {
+	for _, global := range []bool{false, true} {
+		cfg := inlineConfig(t, fmt.Sprintf(`{
+			"keys_only": %v,
+			"versions": {"v1": "http://a"},
+			"test_cases": [
+				{"name": "inherits", "commands": {"v1": "curl {{BASE_URL}}"}},
+				{"name": "on", "keys_only": true, "commands": {"v1": "curl {{BASE_URL}}"}},
+				{"name": "off", "keys_only": false, "commands": {"v1": "curl {{BASE_URL}}"}}
+			]
+		}`, global))
+
+		want := map[string]bool{"inherits": global, "on": true, "off": false}
+		for _, tc := range cfg.GetTestCases() {
+			if got := tc.KeysOnlyFor(cfg); got != want[tc.Name] {
+				t.Errorf("keys_only %v: test case %q KeysOnlyFor = %v, want %v", global, tc.Name, got, want[tc.Name])
+			}
+		}
+	}
+}
+
+func TestParseIgnoreFile(t *testing.T) {
+	data := []byte(`# volatile fields
+data.requestId
+
+  items[].createdAt
+# globs
+**.updatedAt
+meta.*
+`)
+	paths, err := parseIgnoreFile(data)
+	if err != nil {
+		t.Fatalf("parseIgnoreFile: %v", err)
+	}
+	want := []string{"data.requestId", "items[].createdAt", "**.updatedAt", "meta.*"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("paths = %q, want %q", paths, want)
+	}
+
+	if _, err := parseIgnoreFile([]byte("data.id\nitems[x]\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("parseIgnoreFile = %v, want an error on line 2", err)
+	}
+}
+
+func TestLoadAppliesIgnoreFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ignore.txt"), []byte("# comment\n\n**.createdAt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "config.json")
+	cfgData := `{"ignore_paths": ["data.requestId"], "ignore_file": "ignore.txt", "versions": {"v1": "http://a"}, "commands": ["curl {{BASE_URL}}"]}`
+	if err := os.WriteFile(cfgPath, []byte(cfgData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if want := "data.requestId,**.createdAt"; strings.Join(cfg.IgnorePaths, ",") != want {
+		t.Errorf("IgnorePaths = %q, want %s", cfg.IgnorePaths, want)
+	}
+}
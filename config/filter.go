@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter selects which test cases run. A test case is selected when its
+// name is in Names or it carries any of Tags; an empty filter selects all.
+type Filter struct {
+	Names []string `json:"names,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// IsEmpty reports whether the filter selects every test case
+func (f *Filter) IsEmpty() bool {
+	return f == nil || len(f.Names) == 0 && len(f.Tags) == 0
+}
+
+// Matches reports whether a test case is selected by the filter
+func (f *Filter) Matches(tc TestCase) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	for _, name := range f.Names {
+		if tc.Name == name {
+			return true
+		}
+	}
+	for _, want := range f.Tags {
+		for _, tag := range tc.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String describes the filter for error messages
+func (f *Filter) String() string {
+	var parts []string
+	if len(f.Names) > 0 {
+		parts = append(parts, "names: "+strings.Join(f.Names, ", "))
+	}
+	if len(f.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(f.Tags, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SelectedTestCases returns the test cases selected by Filter, failing
+// when a non-empty filter matches nothing
+func (c *Config) SelectedTestCases() ([]TestCase, error) {
+	all := c.GetTestCases()
+	if c.Filter.IsEmpty() {
+		return all, nil
+	}
+
+	var selected []TestCase
+	for _, tc := range all {
+		if c.Filter.Matches(tc) {
+			selected = append(selected, tc)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no test cases match the filter (%s)", c.Filter)
+	}
+	return selected, nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"api_diff_checker/comparator"
+)
+
+// loadIgnoreFile appends the paths listed in IgnoreFile to IgnorePaths. The
+// file holds one path or glob per line, in the ignore_paths syntax; blank
+// lines and lines starting with # are skipped. A relative IgnoreFile is resolved
+// against the config file's directory.
+func (c *Config) loadIgnoreFile() error {
+	if c.IgnoreFile == "" {
+		return nil
+	}
+	path := c.ResolvePath(c.IgnoreFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ignore_file: %w", err)
+	}
+	paths, err := parseIgnoreFile(data)
+	if err != nil {
+		return fmt.Errorf("invalid ignore_file %s: %w", path, err)
+	}
+	c.IgnorePaths = append(c.IgnorePaths, paths...)
+	return nil
+}
+
+// parseIgnoreFile returns the paths of an ignore file, validating each
+func parseIgnoreFile(data []byte) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" || strings.HasPrefix(path, "#") {
+			continue
+		}
+		if err := comparator.ValidateIgnorePath(path); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, scanner.Err()
+}
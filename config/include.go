@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// includeKey is the config field listing files to merge in
+const includeKey = "include"
+
+// readWithIncludes reads a config file and merges the files it includes.
+// Included files are applied in order, then the including file itself:
+// later files override earlier top-level keys, except that "versions" maps
+// are merged and "test_cases" lists are concatenated. stack holds the files
+// currently being loaded, to detect cycles.
+func readWithIncludes(path string, stack []string) (map[string]json.RawMessage, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	for i, p := range stack {
+		if p == abs {
+			cycle := append(append([]string{}, stack[i:]...), abs)
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	stack = append(stack, abs)
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config JSON in %s: %w", path, err)
+	}
+
+	var includes []string
+	if raw, ok := doc[includeKey]; ok {
+		if err := json.Unmarshal(raw, &includes); err != nil {
+			return nil, fmt.Errorf("invalid include in %s: must be a list of paths", path)
+		}
+		delete(doc, includeKey)
+	}
+
+	merged := make(map[string]json.RawMessage)
+	for _, inc := range includes {
+		if !filepath.IsAbs(inc) {
+			inc = filepath.Join(filepath.Dir(abs), inc)
+		}
+		included, err := readWithIncludes(inc, stack)
+		if err != nil {
+			return nil, err
+		}
+		if merged, err = mergeConfigDocs(merged, included); err != nil {
+			return nil, err
+		}
+	}
+	return mergeConfigDocs(merged, doc)
+}
+
+// mergeConfigDocs applies overlay on top of base
+func mergeConfigDocs(base, overlay map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	for key, raw := range overlay {
+		prev, exists := base[key]
+		if !exists {
+			base[key] = raw
+			continue
+		}
+
+		switch key {
+		case "versions":
+			var a, b map[string]string
+			if err := json.Unmarshal(prev, &a); err != nil {
+				return nil, fmt.Errorf("invalid versions: %w", err)
+			}
+			if err := json.Unmarshal(raw, &b); err != nil {
+				return nil, fmt.Errorf("invalid versions: %w", err)
+			}
+			if a == nil {
+				a = make(map[string]string)
+			}
+			for name, url := range b {
+				a[name] = url
+			}
+			base[key], _ = json.Marshal(a)
+		case "test_cases":
+			var a, b []json.RawMessage
+			if err := json.Unmarshal(prev, &a); err != nil {
+				return nil, fmt.Errorf("invalid test_cases: %w", err)
+			}
+			if err := json.Unmarshal(raw, &b); err != nil {
+				return nil, fmt.Errorf("invalid test_cases: %w", err)
+			}
+			base[key], _ = json.Marshal(append(a, b...))
+		default:
+			base[key] = raw
+		}
+	}
+	return base, nil
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"api_diff_checker/config"
+)
+
+// AssertionResult is the outcome of checking a version's response against
+// its test case's expectation
+type AssertionResult struct {
+	Version  string   `json:"version"`
+	Passed   bool     `json:"passed"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// evaluateExpect checks a single response against an expectation.
+// execErr is the execution error, if any; statusCode is 0 when unknown.
+func evaluateExpect(expect config.Expectation, version string, statusCode int, body []byte, execErr string) AssertionResult {
+	result := AssertionResult{Version: version}
+	fail := func(format string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+
+	if execErr != "" {
+		fail("execution failed: %s", execErr)
+		return result
+	}
+
+	if expect.Status != 0 {
+		if statusCode == 0 {
+			fail("status code unavailable, expected %d", expect.Status)
+		} else if statusCode != expect.Status {
+			fail("status %d, expected %d", statusCode, expect.Status)
+		}
+	}
+
+	for _, s := range expect.BodyContains {
+		if !bytes.Contains(body, []byte(s)) {
+			fail("body does not contain %q", s)
+		}
+	}
+
+	result.Passed = len(result.Failures) == 0
+	return result
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/config"
+	"api_diff_checker/schema"
+)
+
+// SuccessCheck is the outcome of evaluating a version's success criteria
+type SuccessCheck struct {
+	Version  string   `json:"version"`
+	Passed   bool     `json:"passed"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// evaluateSuccess checks a single response against its success criteria.
+// execErr is the execution error, if any; statusCode is 0 when unknown.
+func evaluateSuccess(criteria config.SuccessCriteria, version string, statusCode int, body []byte, execErr string) SuccessCheck {
+	check := SuccessCheck{Version: version}
+	fail := func(format string, args ...interface{}) {
+		check.Failures = append(check.Failures, fmt.Sprintf(format, args...))
+	}
+
+	if execErr != "" {
+		fail("execution failed: %s", execErr)
+		return check
+	}
+
+	if len(criteria.Status) > 0 {
+		if statusCode == 0 {
+			fail("status code unavailable, expected one of %s", formatCodes(criteria.Status))
+		} else if !containsCode(criteria.Status, statusCode) {
+			fail("status %d, expected one of %s", statusCode, formatCodes(criteria.Status))
+		}
+	}
+
+	needsJSON := criteria.ExpectJSON || len(criteria.NonEmpty) > 0 || criteria.Schema != ""
+	if !needsJSON {
+		check.Passed = len(check.Failures) == 0
+		return check
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		fail("response is not valid JSON: %v", err)
+		return check
+	}
+
+	for _, path := range criteria.NonEmpty {
+		value, ok := comparator.LookupPath(doc, path)
+		if !ok {
+			fail("path '%s' is missing", path)
+		} else if isEmptyValue(value) {
+			fail("path '%s' is empty", path)
+		}
+	}
+
+	if criteria.Schema != "" {
+		s, err := schema.Load(criteria.Schema)
+		if err != nil {
+			fail("%v", err)
+		} else {
+			for _, violation := range s.Validate(doc) {
+				fail("schema: %s", violation)
+			}
+		}
+	}
+
+	check.Passed = len(check.Failures) == 0
+	return check
+}
+
+// isEmptyValue reports whether a decoded JSON value is null or an empty string, array or object
+func isEmptyValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+	return false
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func formatCodes(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, c := range codes {
+		parts[i] = fmt.Sprint(c)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -2,9 +2,14 @@ package core
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,11 +26,57 @@ const DefaultRunTimeout = 10 * time.Minute
 type Engine struct {
 	Store  *storage.Store
 	Logger *logger.Logger
+
+	// NoRecover disables panic recovery in execution goroutines so panics
+	// crash with a full stack trace. Intended for debugging only.
+	NoRecover bool
+
+	// Comparer diffs each pair of responses (nil = comparator.DefaultComparer)
+	Comparer comparator.Comparer
+
+	// Executor runs every command. When nil, the config's engine picks
+	// executor.NativeExecutor or executor.DefaultExecutor (curl).
+	Executor executor.Executor
 }
 
 type RunResult struct {
 	CommandResults []CommandResult `json:"command_results"`
 	Errors         []string        `json:"errors,omitempty"` // Aggregated non-fatal errors
+
+	// ThresholdExceeded is set when max_changed_fields_percent is configured
+	// and at least one version pair changed more than allowed
+	ThresholdExceeded bool `json:"threshold_exceeded,omitempty"`
+
+	// ChecksFailed is set when any version failed its success criteria
+	ChecksFailed bool `json:"checks_failed,omitempty"`
+
+	// AssertionsFailed is set when any version failed its test case's expect block
+	AssertionsFailed bool `json:"assertions_failed,omitempty"`
+
+	// SchemaDrift is set when any response departed from its test case's schema
+	SchemaDrift bool `json:"schema_drift,omitempty"`
+
+	// Skipped lists versions that were not executed because a test case has
+	// no command for them
+	Skipped []SkippedVersion `json:"skipped,omitempty"`
+
+	// Baseline is the version every other version was compared against
+	// (empty when adjacent versions were compared)
+	Baseline string `json:"baseline,omitempty"`
+
+	// StoppedEarly is set when fail_fast ended the run before every test case
+	// ran; StopReason names the test case that triggered it
+	StoppedEarly bool   `json:"stopped_early,omitempty"`
+	StopReason   string `json:"stop_reason,omitempty"`
+
+	// Summary aggregates the test cases that ran
+	Summary *RunSummary `json:"summary,omitempty"`
+}
+
+// SkippedVersion identifies a version skipped by a test case
+type SkippedVersion struct {
+	TestCase string `json:"test_case"`
+	Version  string `json:"version"`
 }
 
 type CommandResult struct {
@@ -34,6 +85,25 @@ type CommandResult struct {
 	Command      string            `json:"command,omitempty"` // Legacy: single command (kept for backward compat)
 	Diffs        []VersionDiff     `json:"diffs"`
 	ExecInfo     []ExecInfo        `json:"execution_info"` // Version -> FilePath/Exec details
+
+	// Checks holds the success criteria outcome per version (only for versions with criteria)
+	Checks []SuccessCheck `json:"checks,omitempty"`
+
+	// Assertions holds the expect outcome per executed version (only when the test case sets expect)
+	Assertions []AssertionResult `json:"assertions,omitempty"`
+
+	// SchemaDiffs compares each executed version with its schema (only for
+	// versions the test case declares a schema for)
+	SchemaDiffs []SchemaDiff `json:"schema_diffs,omitempty"`
+
+	// Stability reports variation across repeated runs of each version
+	// (only with repeat)
+	Stability []Stability `json:"stability,omitempty"`
+
+	// MultiDiff compares every version that returned a response field by
+	// field (only with compare_mode "multi" and at least two responses)
+	MultiDiff  *comparator.MultiDiffResult `json:"multi_diff,omitempty"`
+	MultiError string                      `json:"multi_error,omitempty"`
 }
 
 type ExecInfo struct {
@@ -41,6 +111,23 @@ type ExecInfo struct {
 	File     string `json:"file"`
 	Error    string `json:"error,omitempty"`
 	TimedOut bool   `json:"timed_out,omitempty"`
+
+	// StatusCode is the HTTP status of the response (0 if it was not captured)
+	StatusCode int `json:"status_code,omitempty"`
+
+	// ContentType is the response's Content-Type header, if it was captured
+	ContentType string `json:"content_type,omitempty"`
+
+	// FinalURL is the URL that produced the response, after Redirects redirects
+	FinalURL  string `json:"final_url,omitempty"`
+	Redirects int    `json:"redirects,omitempty"`
+
+	// Truncated is set when the response exceeded max_response_bytes
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Partial is set when the command timed out and File holds the part of
+	// the response received before the timeout
+	Partial bool `json:"partial,omitempty"`
 }
 
 type VersionDiff struct {
@@ -50,8 +137,80 @@ type VersionDiff struct {
 	OldContent string                 `json:"old_content,omitempty"`
 	NewContent string                 `json:"new_content,omitempty"`
 	Error      string                 `json:"error,omitempty"`
+
+	// RequestDiff compares the resolved requests of both versions (only when compare_requests is set)
+	RequestDiff  *comparator.DiffResult `json:"request_diff,omitempty"`
+	RequestError string                 `json:"request_error,omitempty"`
+
+	// ChangedPercent is the percentage of leaf fields that changed.
+	// ExceedsThreshold is set when it is above max_changed_fields_percent.
+	ChangedPercent   float64 `json:"changed_percent"`
+	ExceedsThreshold bool    `json:"exceeds_threshold,omitempty"`
+
+	// HeaderDiff compares response headers (only when compare_headers is set
+	// and both executions captured headers)
+	HeaderDiff *comparator.HeaderDiff `json:"header_diff,omitempty"`
+
+	// TrailerDiff compares HTTP trailers (only when compare_trailers is set and both executions succeeded)
+	TrailerDiff *comparator.HeaderDiff `json:"trailer_diff,omitempty"`
+
+	// StatusA and StatusB are the HTTP status codes of both responses (0 if
+	// not captured). StatusChanged is set when both are known and differ.
+	StatusA       int  `json:"status_a,omitempty"`
+	StatusB       int  `json:"status_b,omitempty"`
+	StatusChanged bool `json:"status_changed,omitempty"`
+
+	// FinalURLA and FinalURLB are the URLs each response came from after
+	// redirects. EndpointChanged is set when either side was redirected and
+	// the paths differ (hosts are expected to differ between versions).
+	FinalURLA       string `json:"final_url_a,omitempty"`
+	FinalURLB       string `json:"final_url_b,omitempty"`
+	EndpointChanged bool   `json:"endpoint_changed,omitempty"`
+
+	// SizeLimited is set when either response was cut at max_response_bytes,
+	// so the diff covers only their beginnings
+	SizeLimited bool `json:"size_limited,omitempty"`
+
+	// PartialA and PartialB are set when that version timed out and only the
+	// part of its response received before the timeout was compared
+	PartialA bool `json:"partial_a,omitempty"`
+	PartialB bool `json:"partial_b,omitempty"`
+
+	// ExpectedChanges lists the changes acknowledged by expected_diffs.
+	// Expected is set when they cover every change and the status is
+	// unchanged; such a pair doesn't count as a difference.
+	ExpectedChanges []ExpectedChange `json:"expected_changes,omitempty"`
+	Expected        bool             `json:"expected,omitempty"`
 }
 
+// Progress event types
+const (
+	ProgressRunStarted        = "run_started"
+	ProgressVersionExecuted   = "version_executed"
+	ProgressDiffCompleted     = "diff_completed"
+	ProgressTestCaseCompleted = "test_case_completed"
+	ProgressRunCompleted      = "run_completed"
+)
+
+// ProgressEvent reports how far a run has progressed
+type ProgressEvent struct {
+	Type      string `json:"type"`
+	TestCase  string `json:"test_case,omitempty"` // Set for test case, version and diff events
+	Version   string `json:"version,omitempty"`   // Version that executed (version_executed)
+	VersionA  string `json:"version_a,omitempty"` // Compared pair (diff_completed)
+	VersionB  string `json:"version_b,omitempty"`
+	Summary   string `json:"summary,omitempty"` // Diff summary (diff_completed)
+	Error     string `json:"error,omitempty"`   // Execution or comparison error, if any
+	Completed int    `json:"completed"`         // Test cases finished so far
+	Total     int    `json:"total"`             // Test cases planned for the run
+	Diffs     int    `json:"diffs"`             // Version pairs with differences so far
+	Errors    int    `json:"errors"`            // Version pairs that failed so far
+}
+
+// ProgressFunc receives progress events. It is called synchronously from the
+// run loop, so it should return quickly.
+type ProgressFunc func(ProgressEvent)
+
 func NewEngine(store *storage.Store, l *logger.Logger) *Engine {
 	return &Engine{
 		Store:  store,
@@ -59,12 +218,54 @@ func NewEngine(store *storage.Store, l *logger.Logger) *Engine {
 	}
 }
 
+// NewEngineWithComparer creates an engine that diffs responses with c
+// instead of the built-in comparison
+func NewEngineWithComparer(store *storage.Store, l *logger.Logger, c comparator.Comparer) *Engine {
+	e := NewEngine(store, l)
+	e.Comparer = c
+	return e
+}
+
+// NewEngineWithExecutor creates an engine that runs commands with x, e.g. a
+// fake in tests or a client for another transport
+func NewEngineWithExecutor(store *storage.Store, l *logger.Logger, x executor.Executor) *Engine {
+	e := NewEngine(store, l)
+	e.Executor = x
+	return e
+}
+
+// executorFor returns the Executor commands of cfg run with
+func (e *Engine) executorFor(cfg *config.Config) executor.Executor {
+	switch {
+	case e.Executor != nil:
+		return e.Executor
+	case cfg.Engine == config.EngineNative:
+		return executor.NativeExecutor{}
+	}
+	return executor.DefaultExecutor
+}
+
+// comparer returns the Comparer responses are diffed with
+func (e *Engine) comparer() comparator.Comparer {
+	if e.Comparer == nil {
+		return comparator.DefaultComparer
+	}
+	return e.Comparer
+}
+
 // execResult is used for collecting results from goroutines via channel
 type execResult struct {
 	version  string
 	filePath string
+	trailers map[string]string
+	headers  map[string]string
+	response []byte
+	status   int
 	execInfo ExecInfo
-	err      error
+
+	// stability is set when the command was repeated
+	stability *Stability
+	err       error
 }
 
 func (e *Engine) Run(cfg *config.Config) (*RunResult, error) {
@@ -72,6 +273,15 @@ func (e *Engine) Run(cfg *config.Config) (*RunResult, error) {
 }
 
 func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunResult, error) {
+	return e.RunWithProgress(ctx, cfg, nil)
+}
+
+// RunWithProgress runs the configuration and reports progress to the optional callback
+func (e *Engine) RunWithProgress(ctx context.Context, cfg *config.Config, progress ProgressFunc) (*RunResult, error) {
+	if progress == nil {
+		progress = func(ProgressEvent) {}
+	}
+
 	// Apply overall timeout if context doesn't have one
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		var cancel context.CancelFunc
@@ -86,23 +296,83 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 	}
 	sort.Strings(versions)
 
+	if _, ok := cfg.Versions[cfg.Baseline]; cfg.Baseline != "" && !ok {
+		return nil, fmt.Errorf("baseline version '%s' is not defined in versions", cfg.Baseline)
+	}
+
 	// Get normalized test cases (handles both new and legacy formats)
-	testCases := cfg.GetTestCases()
+	testCases, err := cfg.SelectedTestCases()
+	if err != nil {
+		return nil, err
+	}
 
 	runResult := &RunResult{
 		CommandResults: make([]CommandResult, len(testCases)),
+		Baseline:       cfg.Baseline,
 	}
 
 	timeout := cfg.GetTimeout()
+	baseOpts := baseExecOptions(cfg)
+	// Repeated runs are diffed with the options that shape every comparison
+	stabilityOpts := comparator.CompareOptions{
+		IgnorePaths:   cfg.IgnorePaths,
+		ArrayKeys:     cfg.ArrayKeys,
+		MaskRules:     cfg.MaskRules,
+		SortArrays:    cfg.SortArrays,
+		Canonicalize:  cfg.Canonicalize,
+		StripPrefixes: cfg.StripPrefixes,
+	}
+	maxResponseBytes := cfg.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = executor.DefaultMaxResponseBytes
+	}
+
+	// Detect missing binaries (e.g. curl on minimal CI images) once up front,
+	// so a matrix produces one clear error instead of N cryptic exec failures
+	// (native execution, or a custom Executor, needs no external binary)
+	missingTools := make(map[string]error)
+	if cfg.Engine != config.EngineNative && e.Executor == nil {
+		missingTools = e.checkTools(testCases)
+	}
+	for _, err := range uniqueErrors(missingTools) {
+		runResult.Errors = append(runResult.Errors, err.Error())
+	}
 
-	for tcIdx, testCase := range testCases {
-		// Check if context is cancelled
-		select {
-		case <-ctx.Done():
-			runResult.Errors = append(runResult.Errors, fmt.Sprintf("operation cancelled: %v", ctx.Err()))
-			return runResult, ctx.Err()
-		default:
+	// Make skipped TLS verification impossible to miss
+	for _, v := range versions {
+		if cfg.InsecureFor(v) {
+			e.Logger.LogWarn(v, "TLS certificate verification is disabled (insecure_skip_verify)")
 		}
+	}
+
+	// Slots shared by every execution of the run (nil = unlimited)
+	var slots chan struct{}
+	if cfg.MaxConcurrency > 0 {
+		slots = make(chan struct{}, cfg.MaxConcurrency)
+	}
+
+	event := ProgressEvent{Type: ProgressRunStarted, Total: len(testCases)}
+	progress(event)
+
+	// Test cases may run in parallel, so run-wide state is only touched
+	// under mu and progress is reported one event at a time
+	var mu sync.Mutex
+	report := func(ev ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		ev.Completed, ev.Total, ev.Diffs, ev.Errors = event.Completed, event.Total, event.Diffs, event.Errors
+		progress(ev)
+	}
+	skipped := make([][]SkippedVersion, len(testCases)) // Per test case, flattened in order at the end
+	stopAt := -1                                        // Index of the test case that triggered fail_fast
+	cancelled := false
+	// Each test case runs under its own context, so fail_fast can stop the
+	// ones after the failing test case without touching earlier ones
+	cancelCase := make([]context.CancelFunc, len(testCases))
+
+	runTestCase := func(ctx context.Context, tcIdx int, testCase config.TestCase) {
+		// Run-wide flags raised by this test case, merged when it completes
+		var flags RunResult
 
 		cmdRes := CommandResult{
 			TestCaseName: testCase.Name,
@@ -122,9 +392,18 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 			if !ok {
 				// Version not in this test case, skip
 				fmt.Printf("[WARN] Test case '%s' has no command for version '%s', skipping\n", testCase.Name, vName)
+				skipped[tcIdx] = append(skipped[tcIdx], SkippedVersion{TestCase: testCase.Name, Version: vName})
 				continue
 			}
 
+			execOpts := execOptionsFor(cfg, baseOpts, testCase, vName)
+			execOpts.Context = ctx
+			// Native responses stream straight to disk unless a check needs
+			// the body in memory
+			_, hasCriteria := testCase.SuccessFor(vName)
+			_, hasSchema := testCase.SchemaFor(vName)
+			streamable := testCase.Expect == nil && !hasCriteria && !hasSchema && cfg.Repeat <= 1
+
 			wg.Add(1)
 
 			go func(v, url, cmdRaw string) {
@@ -132,6 +411,9 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 
 				// Panic recovery
 				defer func() {
+					if e.NoRecover {
+						return
+					}
 					if r := recover(); r != nil {
 						errMsg := fmt.Sprintf("panic during execution: %v", r)
 						e.Logger.Log(logger.LogEntry{
@@ -149,7 +431,51 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 					}
 				}()
 
-				res, err := executor.Execute(cmdRaw, v, url, timeout)
+				meta := storage.ResponseMeta{
+					ResolvedCommand: recordedCommand(execOpts),
+					TestCase:        testCase.Name,
+				}
+
+				if toolErr, missing := missingTools[cmdRaw]; missing {
+					result := execResult{
+						version:  v,
+						execInfo: ExecInfo{Version: v, Error: toolErr.Error()},
+						err:      toolErr,
+					}
+					_, _ = e.Store.SaveResponseWithMeta(cmdRaw, v, nil, toolErr, meta)
+					resultChan <- result
+					return
+				}
+
+				if slots != nil {
+					select {
+					case slots <- struct{}{}:
+						defer func() { <-slots }()
+					case <-ctx.Done():
+						err := fmt.Errorf("operation cancelled: %w", ctx.Err())
+						resultChan <- execResult{
+							version:  v,
+							execInfo: ExecInfo{Version: v, Error: err.Error()},
+							err:      err,
+						}
+						return
+					}
+				}
+
+				e.Logger.LogDebug(v, "Executing: "+storage.RedactCommand(meta.ResolvedCommand))
+
+				execute := e.executorFor(cfg)
+				if cfg.Engine == config.EngineNative && streamable {
+					execOpts.ResponseSink = func(res *executor.ExecutionResult, body io.Reader) (string, error) {
+						streamMeta := meta
+						streamMeta.StatusCode, streamMeta.Headers = res.StatusCode, res.Headers
+						streamMeta.ContentType = res.ContentType
+						path, truncated, err := e.Store.SaveResponseStream(cmdRaw, v, body, maxResponseBytes, streamMeta)
+						res.Truncated = truncated
+						return path, err
+					}
+				}
+				res, err := execute.Execute(execOpts)
 				result := execResult{
 					version:  v,
 					execInfo: ExecInfo{Version: v, TimedOut: res != nil && res.TimedOut},
@@ -160,14 +486,44 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 						Level: "ERROR", Version: v, Command: cmdRaw,
 						Message: "Execution failed", ErrorDetails: err.Error(),
 					})
-					_, _ = e.Store.SaveResponse(cmdRaw, v, nil, err)
 					result.execInfo.Error = err.Error()
 					if res != nil && res.TimedOut {
 						result.execInfo.Error = fmt.Sprintf("timeout after %s", timeout)
 					}
 					result.err = err
+
+					if res != nil && res.Partial {
+						// Keep what arrived before the timeout; it is diffed
+						// but still counts as a failed execution
+						meta.StatusCode, meta.ContentType, meta.Partial = res.StatusCode, res.ContentType, true
+						path := res.ResponseFile
+						if path == "" {
+							path, _ = e.Store.SaveResponseWithMeta(cmdRaw, v, res.Response, err, meta)
+						}
+						e.Logger.Log(logger.LogEntry{Level: "WARN", Version: v, Command: cmdRaw, Message: "Partial response saved", ErrorDetails: path})
+						result.execInfo.File = path
+						result.execInfo.Partial = true
+						result.execInfo.StatusCode = res.StatusCode
+						result.execInfo.ContentType = res.ContentType
+						result.filePath = path
+						result.status = res.StatusCode
+					} else {
+						_, _ = e.Store.SaveResponseWithMeta(cmdRaw, v, nil, err, meta)
+					}
 				} else {
-					path, saveErr := e.Store.SaveResponse(cmdRaw, v, res.Response, nil)
+					meta.StatusCode = res.StatusCode
+					meta.Headers = res.Headers
+					meta.ContentType = res.ContentType
+					meta.Truncated = res.Truncated
+					result.execInfo.StatusCode = res.StatusCode
+					result.execInfo.ContentType = res.ContentType
+					result.execInfo.FinalURL = res.FinalURL
+					result.execInfo.Redirects = res.Redirects
+					result.execInfo.Truncated = res.Truncated
+					path, saveErr := res.ResponseFile, error(nil)
+					if path == "" {
+						path, saveErr = e.Store.SaveResponseWithMeta(cmdRaw, v, res.Response, nil, meta)
+					}
 					if saveErr != nil {
 						e.Logger.Log(logger.LogEntry{Level: "ERROR", Version: v, Message: "Failed to save response", ErrorDetails: saveErr.Error()})
 						result.execInfo.Error = "Save failed: " + saveErr.Error()
@@ -176,6 +532,15 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 						e.Logger.Log(logger.LogEntry{Level: "INFO", Version: v, Command: cmdRaw, Message: "Response saved", ErrorDetails: path})
 						result.execInfo.File = path
 						result.filePath = path
+						result.trailers = res.Trailers
+						result.headers = res.Headers
+						result.response = res.Response
+						result.status = res.StatusCode
+
+						if cfg.Repeat > 1 {
+							stability := measureStability(ctx, execute, e.comparer(), execOpts, res.Response, cfg.Repeat, stabilityOpts)
+							result.stability = &stability
+						}
 					}
 				}
 
@@ -183,93 +548,658 @@ func (e *Engine) RunWithContext(ctx context.Context, cfg *config.Config) (*RunRe
 			}(vName, baseURL, cmdForVersion)
 		}
 
-		// Wait for all goroutines to complete
-		wg.Wait()
-		close(resultChan)
+		// Close the channel once every goroutine is done, so results can be
+		// collected (and reported) as they arrive
+		go func() {
+			wg.Wait()
+			close(resultChan)
+		}()
 
 		// Collect results from channel (thread-safe)
-		results := make(map[string]string) // Version -> FilePath
+		results := make(map[string]string)             // Version -> FilePath
+		trailers := make(map[string]map[string]string) // Version -> Trailers
+		headers := make(map[string]map[string]string)  // Version -> Headers
+		executed := make(map[string]execResult)        // Version -> Result
 		for result := range resultChan {
+			executed[result.version] = result
+			if result.stability != nil {
+				cmdRes.Stability = append(cmdRes.Stability, *result.stability)
+			}
 			cmdRes.ExecInfo = append(cmdRes.ExecInfo, result.execInfo)
 			if result.filePath != "" {
 				results[result.version] = result.filePath
 			}
+			if result.trailers != nil {
+				trailers[result.version] = result.trailers
+			}
+			if result.headers != nil {
+				headers[result.version] = result.headers
+			}
+			report(ProgressEvent{
+				Type:     ProgressVersionExecuted,
+				TestCase: testCase.Name,
+				Version:  result.version,
+				Error:    result.execInfo.Error,
+			})
 		}
 
 		// Sort ExecInfo by version for consistent display
 		sort.Slice(cmdRes.ExecInfo, func(i, j int) bool {
 			return cmdRes.ExecInfo[i].Version < cmdRes.ExecInfo[j].Version
 		})
+		sort.Slice(cmdRes.Stability, func(i, j int) bool {
+			return cmdRes.Stability[i].Version < cmdRes.Stability[j].Version
+		})
+
+		// Evaluate per-version success criteria
+		for _, vName := range versions {
+			criteria, hasCriteria := testCase.SuccessFor(vName)
+			res, ran := executed[vName]
+			if !hasCriteria || !ran {
+				continue
+			}
+			check := evaluateSuccess(criteria, vName, res.status, res.response, res.execInfo.Error)
+			if !check.Passed {
+				flags.ChecksFailed = true
+			}
+			cmdRes.Checks = append(cmdRes.Checks, check)
+		}
+
+		// Evaluate the test case's expectation against every executed version
+		if testCase.Expect != nil {
+			for _, vName := range versions {
+				res, ran := executed[vName]
+				if !ran {
+					continue
+				}
+				assertion := evaluateExpect(*testCase.Expect, vName, res.status, res.response, res.execInfo.Error)
+				if !assertion.Passed {
+					flags.AssertionsFailed = true
+				}
+				cmdRes.Assertions = append(cmdRes.Assertions, assertion)
+			}
+		}
+
+		// Compare responses with the schemas declared for their versions
+		for _, vName := range versions {
+			ref, hasSchema := testCase.SchemaFor(vName)
+			res, ran := executed[vName]
+			if !hasSchema || !ran {
+				continue
+			}
+			schemaDiff := SchemaDiff{Schema: cfg.ResolvePath(ref)}
+			if res.execInfo.Error != "" {
+				schemaDiff.Error = "execution failed: " + res.execInfo.Error
+			} else {
+				schemaDiff = compareAgainstSchema(res.response, schemaDiff.Schema)
+			}
+			schemaDiff.Version = vName
+			if schemaDiff.HasDrift() {
+				flags.SchemaDrift = true
+			}
+			cmdRes.SchemaDiffs = append(cmdRes.SchemaDiffs, schemaDiff)
+		}
 
 		// Compare versions
-		if len(versions) > 1 {
-			for i := 0; i < len(versions)-1; i++ {
-				vBase := versions[i]
-				vTarget := versions[i+1]
+		reader := newResponseReader(cfg.Normalizer)
+		for _, pair := range versionPairs(versions, cfg.Baseline) {
+			vBase, vTarget := pair[0], pair[1]
 
-				file1, ok1 := results[vBase]
-				file2, ok2 := results[vTarget]
+			file1, ok1 := results[vBase]
+			file2, ok2 := results[vTarget]
 
-				vDiff := VersionDiff{
-					VersionA: vBase,
-					VersionB: vTarget,
-				}
+			vDiff := VersionDiff{
+				VersionA: vBase,
+				VersionB: vTarget,
+				StatusA:  executed[vBase].status,
+				StatusB:  executed[vTarget].status,
+			}
+			vDiff.StatusChanged = comparator.StatusChange(vDiff.StatusA, vDiff.StatusB) != ""
+			vDiff.FinalURLA = executed[vBase].execInfo.FinalURL
+			vDiff.FinalURLB = executed[vTarget].execInfo.FinalURL
+			vDiff.SizeLimited = executed[vBase].execInfo.Truncated || executed[vTarget].execInfo.Truncated
+			vDiff.PartialA = executed[vBase].execInfo.Partial
+			vDiff.PartialB = executed[vTarget].execInfo.Partial
+			if executed[vBase].execInfo.Redirects > 0 || executed[vTarget].execInfo.Redirects > 0 {
+				vDiff.EndpointChanged = endpointChanged(vDiff.FinalURLA, vDiff.FinalURLB)
+			}
 
-				if ok1 && ok2 {
-					diff, old, new, err := e.compareFiles(file1, file2, vBase, vTarget, cfg.KeysOnly)
+			if cfg.CompareRequests {
+				cmdA, okA := testCase.Commands[vBase]
+				cmdB, okB := testCase.Commands[vTarget]
+				if okA && okB {
+					reqDiff, err := compareRequests(
+						cmdA, cfg.Versions[vBase], execOptionsFor(cfg, baseOpts, testCase, vBase),
+						cmdB, cfg.Versions[vTarget], execOptionsFor(cfg, baseOpts, testCase, vTarget),
+						vBase, vTarget)
 					if err != nil {
-						vDiff.Error = err.Error()
+						vDiff.RequestError = err.Error()
 					} else {
-						vDiff.DiffResult = diff
-						vDiff.OldContent = old
-						vDiff.NewContent = new
+						vDiff.RequestDiff = reqDiff
 					}
+				}
+			}
+
+			if cfg.CompareHeaders {
+				h1, okH1 := headers[vBase]
+				h2, okH2 := headers[vTarget]
+				if okH1 && okH2 {
+					vDiff.HeaderDiff = comparator.CompareHeaders(h1, h2, cfg.IgnoreHeaders, "header")
+				}
+			}
+
+			// Executions without trailers leave them nil, so once both
+			// succeeded a missing map is compared as empty: trailers that
+			// disappear in one version are a change
+			if cfg.CompareTrailers && ok1 && ok2 {
+				vDiff.TrailerDiff = comparator.CompareHeaders(trailers[vBase], trailers[vTarget], nil, "trailer")
+			}
+
+			if ok1 && ok2 {
+				opts := comparator.CompareOptions{
+					KeysOnly:         testCase.KeysOnlyFor(cfg),
+					LabelPath:        testCase.LabelPath,
+					StreamThreshold:  cfg.StreamThresholdBytes,
+					CardinalityPaths: testCase.CardinalityPaths,
+					IgnorePaths:      cfg.IgnorePaths,
+					GroupBySection:   cfg.GroupBySection,
+					ArrayKeys:        cfg.ArrayKeys,
+					MaskRules:        cfg.MaskRules,
+					Canonicalize:     cfg.Canonicalize,
+					SortArrays:       cfg.SortArrays,
+					ContextLines:     cfg.ContextLines,
+					StripPrefixes:    cfg.StripPrefixes,
+
+					OriginalContentType: executed[vBase].execInfo.ContentType,
+					ModifiedContentType: executed[vTarget].execInfo.ContentType,
+				}
+				diff, old, new, err := e.compareFiles(reader, file1, file2,
+					partialLabel(vBase, vDiff.PartialA), partialLabel(vTarget, vDiff.PartialB), opts)
+				if err != nil {
+					vDiff.Error = err.Error()
 				} else {
-					var missing []string
-					if !ok1 {
-						missing = append(missing, vBase)
-					}
-					if !ok2 {
-						missing = append(missing, vTarget)
+					diff.AddStatusChange(vDiff.StatusA, vDiff.StatusB)
+					vDiff.DiffResult = diff
+					vDiff.OldContent = old
+					vDiff.NewContent = new
+					vDiff.ChangedPercent = diff.ChangedPercent()
+					var all bool
+					vDiff.ExpectedChanges, all = matchExpected(cfg.ExpectedDiffsFor(testCase.Name), diff)
+					vDiff.Expected = all && !vDiff.StatusChanged
+					if cfg.MaxChangedFieldsPercent > 0 && vDiff.ChangedPercent > cfg.MaxChangedFieldsPercent && !vDiff.Expected {
+						vDiff.ExceedsThreshold = true
+						flags.ThresholdExceeded = true
 					}
-					vDiff.Error = fmt.Sprintf("failed to get responses for version(s): %s",
-						joinStrings(missing, ", "))
 				}
-				cmdRes.Diffs = append(cmdRes.Diffs, vDiff)
+			} else {
+				var missing []string
+				if !ok1 {
+					missing = append(missing, vBase)
+				}
+				if !ok2 {
+					missing = append(missing, vTarget)
+				}
+				vDiff.Error = fmt.Sprintf("failed to get responses for version(s): %s",
+					joinStrings(missing, ", "))
+				if vBase == cfg.Baseline && !ok1 {
+					vDiff.Error = baselineError(vBase, executed)
+				}
+			}
+			cmdRes.Diffs = append(cmdRes.Diffs, vDiff)
+			diffEvent := ProgressEvent{
+				Type:     ProgressDiffCompleted,
+				TestCase: testCase.Name,
+				VersionA: vBase,
+				VersionB: vTarget,
+				Error:    vDiff.Error,
+			}
+			if vDiff.DiffResult != nil {
+				diffEvent.Summary = vDiff.DiffResult.Summary
 			}
+			report(diffEvent)
+		}
+
+		if cfg.CompareMode == config.CompareModeMulti && len(results) >= 2 {
+			cmdRes.MultiDiff, cmdRes.MultiError = compareMulti(reader, results, comparator.CompareOptions{
+				KeysOnly:      testCase.KeysOnlyFor(cfg),
+				IgnorePaths:   cfg.IgnorePaths,
+				MaskRules:     cfg.MaskRules,
+				SortArrays:    cfg.SortArrays,
+				StripPrefixes: cfg.StripPrefixes,
+			})
 		}
 
+		diffs, errs := countOutcomes(cmdRes)
+
+		mu.Lock()
+		defer mu.Unlock()
 		runResult.CommandResults[tcIdx] = cmdRes
+		runResult.ChecksFailed = runResult.ChecksFailed || flags.ChecksFailed
+		runResult.AssertionsFailed = runResult.AssertionsFailed || flags.AssertionsFailed
+		runResult.SchemaDrift = runResult.SchemaDrift || flags.SchemaDrift
+		runResult.ThresholdExceeded = runResult.ThresholdExceeded || flags.ThresholdExceeded
+
+		event.Type = ProgressTestCaseCompleted
+		event.TestCase = testCase.Name
+		event.Completed++
+		event.Diffs += diffs
+		event.Errors += errs
+		progress(event)
+
+		// Every goroutine of this test case has finished; later test cases
+		// running in parallel are cancelled and the rest don't start. The
+		// earliest failing test case wins, as it would sequentially. A test
+		// case that was itself cancelled didn't fail on its own.
+		if cfg.FailFast && (diffs > 0 || errs > 0) && ctx.Err() == nil && tcIdx < len(testCases)-1 && (stopAt < 0 || tcIdx < stopAt) {
+			stopAt = tcIdx
+			runResult.StoppedEarly = true
+			runResult.StopReason = fmt.Sprintf("fail_fast: test case '%s' had %d difference(s) and %d error(s)",
+				testCase.Name, diffs, errs)
+			for i := tcIdx + 1; i < len(cancelCase); i++ {
+				if cancelCase[i] != nil {
+					cancelCase[i]()
+				}
+			}
+		}
 	}
 
+	// Workers take test cases in order; parallel_test_cases of them run at
+	// once (one by default), sharing the max_concurrency slots
+	workers := cfg.ParallelTestCases
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var workersWG sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		workersWG.Add(1)
+		go func() {
+			defer workersWG.Done()
+			for tcIdx := range jobs {
+				mu.Lock()
+				skip := cancelled || (stopAt >= 0 && tcIdx > stopAt)
+				if !skip && stopAt < 0 && ctx.Err() != nil {
+					runResult.Errors = append(runResult.Errors, fmt.Sprintf("operation cancelled: %v", ctx.Err()))
+					cancelled, skip = true, true
+				}
+				caseCtx, cancel := context.WithCancel(ctx)
+				cancelCase[tcIdx] = cancel
+				mu.Unlock()
+				if !skip {
+					runTestCase(caseCtx, tcIdx, testCases[tcIdx])
+				}
+				cancel()
+			}
+		}()
+	}
+	for tcIdx := range testCases {
+		jobs <- tcIdx
+	}
+	close(jobs)
+	workersWG.Wait()
+
+	for _, s := range skipped {
+		runResult.Skipped = append(runResult.Skipped, s...)
+	}
+	if cancelled {
+		return runResult, ctx.Err()
+	}
+	if stopAt >= 0 {
+		runResult.CommandResults = runResult.CommandResults[:stopAt+1]
+	}
+	summary := Summarize(runResult)
+	runResult.Summary = &summary
+
+	event.Type = ProgressRunCompleted
+	event.TestCase = ""
+	progress(event)
+
 	return runResult, nil
 }
 
-func (e *Engine) compareFiles(file1, file2, v1, v2 string, keysOnly bool) (*comparator.DiffResult, string, string, error) {
-	b1, err := os.ReadFile(file1)
+// compareMulti compares the stored responses of every version at once
+func compareMulti(reader *responseReader, files map[string]string, opts comparator.CompareOptions) (*comparator.MultiDiffResult, string) {
+	inputs := make(map[string][]byte, len(files))
+	for version, file := range files {
+		data, err := reader.read(file)
+		if err != nil {
+			return nil, fmt.Sprintf("read %s response: %v", version, err)
+		}
+		inputs[version] = data
+	}
+	multi, err := comparator.CompareMulti(inputs, opts)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return multi, ""
+}
+
+func (e *Engine) compareFiles(reader *responseReader, file1, file2, v1, v2 string, opts comparator.CompareOptions) (*comparator.DiffResult, string, string, error) {
+	name1, name2 := file1, file2
+	if strings.HasSuffix(v1, partialSuffix) {
+		name1 += partialSuffix
+	}
+	if strings.HasSuffix(v2, partialSuffix) {
+		name2 += partialSuffix
+	}
+
+	// Large arrays are diffed straight from disk, and neither body is kept
+	if reader.normalizer == nil && e.Comparer == nil && exceedsThreshold(file1, file2, opts) {
+		if diff, ok := streamFiles(file1, file2, name1, name2, opts); ok {
+			return diff, "", "", nil
+		}
+	}
+
+	b1, err := reader.read(file1)
 	if err != nil {
 		return nil, "", "", fmt.Errorf("read file1 error: %w", err)
 	}
-	b2, err := os.ReadFile(file2)
+	b2, err := reader.read(file2)
 	if err != nil {
 		return nil, "", "", fmt.Errorf("read file2 error: %w", err)
 	}
 
-	if len(b1) == 0 {
-		return nil, "", "", fmt.Errorf("empty response content for %s", v1)
-	}
-	if len(b2) == 0 {
-		return nil, "", "", fmt.Errorf("empty response content for %s", v2)
+	// An empty body is a difference worth reporting, not a failure
+	if comparator.IsEmptyResponse(b1) || comparator.IsEmptyResponse(b2) {
+		return comparator.CompareEmpty(b1, b2, v1, v2), string(b1), string(b2), nil
 	}
 
-	opts := comparator.CompareOptions{KeysOnly: keysOnly}
-	diff, err := comparator.CompareWithOptions(b1, b2, file1, file2, opts)
+	diff, err := e.comparer().Compare(b1, b2, name1, name2, opts)
 	if err != nil {
 		return nil, "", "", err
 	}
 	return diff, string(b1), string(b2), nil
 }
 
+// exceedsThreshold reports whether streaming is enabled and either stored
+// file is larger than the threshold
+func exceedsThreshold(file1, file2 string, opts comparator.CompareOptions) bool {
+	if !comparator.CanStream(opts) {
+		return false
+	}
+	for _, file := range []string{file1, file2} {
+		if info, err := os.Stat(file); err == nil && info.Size() > int64(opts.StreamThreshold) {
+			return true
+		}
+	}
+	return false
+}
+
+// streamFiles compares two stored top-level arrays element by element from
+// disk. It returns false if either file can't be opened or isn't an array,
+// leaving the comparison to the in-memory path.
+func streamFiles(file1, file2, name1, name2 string, opts comparator.CompareOptions) (*comparator.DiffResult, bool) {
+	f1, err := storage.OpenResponse(file1)
+	if err != nil {
+		return nil, false
+	}
+	defer f1.Close()
+	f2, err := storage.OpenResponse(file2)
+	if err != nil {
+		return nil, false
+	}
+	defer f2.Close()
+
+	diff, err := comparator.CompareArrayStreams(f1, f2, name1, name2, opts)
+	return diff, err == nil
+}
+
+// responseReader loads response files for comparison, running the configured
+// normalizer at most once per file
+type responseReader struct {
+	normalizer *config.Normalizer
+	cache      map[string][]byte
+}
+
+func newResponseReader(normalizer *config.Normalizer) *responseReader {
+	return &responseReader{normalizer: normalizer, cache: make(map[string][]byte)}
+}
+
+// read returns the (normalized) content of a response file
+func (r *responseReader) read(path string) ([]byte, error) {
+	if data, ok := r.cache[path]; ok {
+		return data, nil
+	}
+
+	data, err := storage.ReadResponse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.normalizer != nil && len(data) > 0 {
+		out, err := executor.RunFilter(r.normalizer.Command, data, r.normalizer.GetTimeout())
+		if err != nil {
+			return nil, fmt.Errorf("normalizer failed: %w", err)
+		}
+		if !json.Valid(out) {
+			return nil, fmt.Errorf("normalizer did not return valid JSON")
+		}
+		data = out
+	}
+
+	r.cache[path] = data
+	return data, nil
+}
+
+// versionPairs returns the version pairs to compare: each version against the
+// baseline when one is set, otherwise adjacent versions in sorted order
+func versionPairs(versions []string, baseline string) [][2]string {
+	var pairs [][2]string
+	if baseline != "" {
+		for _, v := range versions {
+			if v != baseline {
+				pairs = append(pairs, [2]string{baseline, v})
+			}
+		}
+		return pairs
+	}
+	for i := 0; i < len(versions)-1; i++ {
+		pairs = append(pairs, [2]string{versions[i], versions[i+1]})
+	}
+	return pairs
+}
+
+// baselineError explains why a baseline version has no response to compare against
+func baselineError(baseline string, executed map[string]execResult) string {
+	res, ran := executed[baseline]
+	switch {
+	case !ran:
+		return fmt.Sprintf("baseline version '%s' was not executed for this test case", baseline)
+	case res.execInfo.Error != "":
+		return fmt.Sprintf("baseline version '%s' produced no response: %s", baseline, res.execInfo.Error)
+	}
+	return fmt.Sprintf("baseline version '%s' produced no response", baseline)
+}
+
+// partialSuffix marks a version or file in diffs whose response was cut
+// short by a timeout
+const partialSuffix = " (partial, timed out)"
+
+// partialLabel names a version in diffs, marking a partial response
+func partialLabel(version string, partial bool) string {
+	if partial {
+		return version + partialSuffix
+	}
+	return version
+}
+
+// Outcomes returns how many version pairs across the run differ and how many
+// failed to compare (e.g. an execution error left a version without a response)
+func (r *RunResult) Outcomes() (diffs, errs int) {
+	for _, cmdRes := range r.CommandResults {
+		d, e := countOutcomes(cmdRes)
+		diffs += d
+		errs += e
+	}
+	return diffs, errs
+}
+
+// endpointChanged reports whether two final URLs point at different
+// endpoints, comparing path and query only since versions run on different hosts
+func endpointChanged(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a != b
+	}
+	return ua.Path != ub.Path || ua.RawQuery != ub.RawQuery
+}
+
+// countOutcomes returns how many version pairs of a test case differ and how many failed
+func countOutcomes(cmdRes CommandResult) (diffs, errs int) {
+	for _, d := range cmdRes.Diffs {
+		if d.Failed() {
+			errs++
+		} else if d.Differs() {
+			diffs++
+		}
+	}
+	return diffs, errs
+}
+
+// Failed reports whether the pair could not be compared. A diff of a
+// timed-out response is informative but not a pass, so it counts too.
+func (d VersionDiff) Failed() bool {
+	return d.Error != "" || d.PartialA || d.PartialB
+}
+
+// Differs reports whether the pair was compared and found changes that
+// expected_diffs doesn't cover
+func (d VersionDiff) Differs() bool {
+	return !d.Failed() && d.DiffResult != nil && d.DiffResult.Summary != comparator.NoChangesSummary && !d.Expected
+}
+
+// checkTools verifies the binary of every command is installed. It returns the
+// commands whose binary is missing, mapped to the error, and logs each missing
+// tool once.
+func (e *Engine) checkTools(testCases []config.TestCase) map[string]error {
+	missing := make(map[string]error)
+	reported := make(map[string]bool)
+	for _, tc := range testCases {
+		for _, cmd := range tc.Commands {
+			if _, seen := missing[cmd]; seen {
+				continue
+			}
+			tool, err := executor.CheckCommandTool(cmd)
+			var toolErr *executor.MissingToolError
+			if !errors.As(err, &toolErr) {
+				// Parse errors are reported per execution as before
+				continue
+			}
+			missing[cmd] = err
+			if !reported[tool] {
+				reported[tool] = true
+				e.Logger.Log(logger.LogEntry{
+					Level: "ERROR", Message: "Required tool not installed", ErrorDetails: err.Error(),
+				})
+			}
+		}
+	}
+	return missing
+}
+
+// uniqueErrors returns the distinct errors in the map, sorted by message
+func uniqueErrors(errs map[string]error) []error {
+	seen := make(map[string]bool)
+	var unique []error
+	for _, err := range errs {
+		if !seen[err.Error()] {
+			seen[err.Error()] = true
+			unique = append(unique, err)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].Error() < unique[j].Error() })
+	return unique
+}
+
+// baseExecOptions returns the execution options shared by every command of a run
+func baseExecOptions(cfg *config.Config) executor.ExecuteOptions {
+	injectDelay, injectJitter := cfg.GetInjectedDelay()
+	opts := executor.ExecuteOptions{
+		Timeout:          cfg.GetTimeout(),
+		BaseDir:          cfg.BaseDir,
+		InjectDelay:      injectDelay,
+		InjectJitter:     injectJitter,
+		CaptureHeaders:   cfg.CompareHeaders,
+		MaxResponseBytes: cfg.MaxResponseBytes,
+	}
+	if cfg.HTTP != nil {
+		opts.FollowRedirects = cfg.HTTP.FollowRedirects
+		opts.MaxRedirects = cfg.HTTP.MaxRedirects
+	}
+	return opts
+}
+
+// execOptionsFor returns the execution options for a version of a test case
+func execOptionsFor(cfg *config.Config, base executor.ExecuteOptions, tc config.TestCase, version string) executor.ExecuteOptions {
+	opts := base
+	opts.Command = tc.Commands[version]
+	opts.Version = version
+	opts.BaseURL = cfg.Versions[version]
+	opts.Variables = cfg.VariablesFor(version)
+	opts.Headers = cfg.HeadersFor(version)
+	opts.Body = tc.Body
+	opts.BodyRenames = tc.BodyRenames[version]
+	opts.Proxy = cfg.ProxyFor(version)
+	opts.InsecureSkipVerify = cfg.InsecureFor(version)
+	return opts
+}
+
+// recordedCommand returns the command execOpts runs, as the dry run shows
+// it: with body, headers, redirect, proxy and TLS flags added and secrets
+// redacted. A command that can't be resolved is recorded with only its
+// placeholders substituted.
+func recordedCommand(opts executor.ExecuteOptions) string {
+	args, err := executor.PlanCommand(opts)
+	if err != nil {
+		return executor.ResolveCommandWithVars(opts.Command, opts.BaseURL, opts.Variables)
+	}
+	return storage.RedactArgs(args)
+}
+
+// compareRequests parses both resolved commands into structured requests and
+// diffs them, with credentials and other sensitive headers redacted
+func compareRequests(cmdA, urlA string, optsA executor.ExecuteOptions, cmdB, urlB string, optsB executor.ExecuteOptions, vA, vB string) (*comparator.DiffResult, error) {
+	specA, err := parseResolvedRequest(cmdA, urlA, optsA)
+	if err != nil {
+		return nil, fmt.Errorf("parse request for %s: %w", vA, err)
+	}
+	specB, err := parseResolvedRequest(cmdB, urlB, optsB)
+	if err != nil {
+		return nil, fmt.Errorf("parse request for %s: %w", vB, err)
+	}
+
+	// The diff ends up in results, history, archives and webhooks
+	for _, spec := range []*executor.RequestSpec{specA, specB} {
+		spec.URL = storage.RedactURL(spec.URL)
+		spec.Query = storage.RedactQuery(spec.Query)
+		spec.Headers = storage.RedactHeaders(spec.Headers)
+	}
+
+	b1, err := json.MarshalIndent(specA, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal request for %s: %w", vA, err)
+	}
+	b2, err := json.MarshalIndent(specB, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal request for %s: %w", vB, err)
+	}
+
+	return comparator.Compare(b1, b2, vA+" request", vB+" request")
+}
+
+// parseResolvedRequest resolves a command exactly as it would be executed and parses the request
+func parseResolvedRequest(cmd, baseURL string, opts executor.ExecuteOptions) (*executor.RequestSpec, error) {
+	_, args, err := executor.ResolveArgs(cmd, baseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	return executor.ParseRequestArgs(args)
+}
+
 // joinStrings joins strings with a separator
 func joinStrings(strs []string, sep string) string {
 	if len(strs) == 0 {
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"api_diff_checker/comparator"
+	"api_diff_checker/config"
+)
+
+// ExpectedChange is a changed path covered by the config's expected_diffs
+type ExpectedChange struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// matchExpected returns the changes of diff covered by the expected diffs of
+// a test case, and whether they cover every change. Only structured (JSON)
+// diffs can match; text diffs have no change paths.
+func matchExpected(expected []config.ExpectedDiff, diff *comparator.DiffResult) ([]ExpectedChange, bool) {
+	if len(expected) == 0 || diff == nil || diff.Changes == nil {
+		return nil, false
+	}
+
+	var matched []ExpectedChange
+	total := 0
+	for _, paths := range [][]string{diff.Changes.Added, diff.Changes.Removed, diff.Changes.Changed} {
+		for _, path := range paths {
+			total++
+			if reason, ok := expectedReason(expected, path); ok {
+				matched = append(matched, ExpectedChange{Path: path, Reason: reason})
+			}
+		}
+	}
+	return matched, total > 0 && len(matched) == total
+}
+
+// expectedReason returns the reason of the first expected diff covering path
+func expectedReason(expected []config.ExpectedDiff, path string) (string, bool) {
+	for _, e := range expected {
+		for _, pattern := range e.Paths {
+			if comparator.PathMatches(pattern, path) {
+				return e.Reason, true
+			}
+		}
+	}
+	return "", false
+}
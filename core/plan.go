@@ -0,0 +1,59 @@
+package core
+
+import (
+	"sort"
+
+	"api_diff_checker/config"
+	"api_diff_checker/executor"
+	"api_diff_checker/storage"
+)
+
+// PlannedCommand is a command as a run would execute it for one version
+type PlannedCommand struct {
+	TestCase string `json:"test_case"`
+	Version  string `json:"version"`
+
+	// Command is the resolved command line, with secrets redacted
+	Command string `json:"command,omitempty"`
+
+	// Error is why the command could not be resolved (e.g. an unresolved
+	// placeholder or a missing body file); the run would fail it the same way
+	Error string `json:"error,omitempty"`
+}
+
+// Plan resolves the commands of every selected test case exactly as Run
+// would (placeholders, headers, body, redirect/proxy/TLS flags) without
+// executing anything, saving responses or comparing. Commands are returned
+// per test case in config order and per version in sorted order; versions a
+// test case has no command for are left out.
+func (e *Engine) Plan(cfg *config.Config) ([]PlannedCommand, error) {
+	testCases, err := cfg.SelectedTestCases()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for v := range cfg.Versions {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+
+	baseOpts := baseExecOptions(cfg)
+	var plan []PlannedCommand
+	for _, testCase := range testCases {
+		for _, v := range versions {
+			if _, ok := testCase.Commands[v]; !ok {
+				continue
+			}
+			planned := PlannedCommand{TestCase: testCase.Name, Version: v}
+			args, err := executor.PlanCommand(execOptionsFor(cfg, baseOpts, testCase, v))
+			if err != nil {
+				planned.Error = err.Error()
+			} else {
+				planned.Command = storage.RedactArgs(args)
+			}
+			plan = append(plan, planned)
+		}
+	}
+	return plan, nil
+}
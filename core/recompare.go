@@ -0,0 +1,48 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/storage"
+)
+
+// ErrNoStoredResponse is returned by CompareStored when a version has no
+// successful stored response for the command
+var ErrNoStoredResponse = errors.New("no stored response")
+
+// CompareStored re-diffs the latest stored responses of two versions for a
+// command without executing anything, e.g. to look at an old regression with
+// different options. commandHash may be a unique prefix of the hash (such as
+// the 8 characters in response file names).
+func (e *Engine) CompareStored(commandHash, versionA, versionB string, opts comparator.CompareOptions) (*comparator.DiffResult, error) {
+	fullHash, err := e.Store.MatchCommandHash(commandHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var files, contentTypes [2]string
+	for i, version := range []string{versionA, versionB} {
+		rec, ok := e.Store.LatestFor(fullHash, version)
+		if !ok || rec.ResponseFile == "" {
+			return nil, fmt.Errorf("%w for version '%s' of command %s", ErrNoStoredResponse, version, fullHash[:8])
+		}
+		if rec.Status != "success" {
+			return nil, fmt.Errorf("%w for version '%s' of command %s: latest execution failed: %s",
+				ErrNoStoredResponse, version, fullHash[:8], rec.Error)
+		}
+		files[i] = e.Store.GetResponsePath(rec.ResponseFile)
+		contentTypes[i] = rec.ContentType
+	}
+	opts.OriginalContentType, opts.ModifiedContentType = contentTypes[0], contentTypes[1]
+
+	diff, _, _, err := e.compareFiles(newResponseReader(nil), files[0], files[1], versionA, versionB, opts)
+	return diff, err
+}
+
+// IsNotStored reports whether a CompareStored error means the command or a
+// version's response is not in the store
+func IsNotStored(err error) bool {
+	return errors.Is(err, ErrNoStoredResponse) || errors.Is(err, storage.ErrCommandNotFound)
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"api_diff_checker/schema"
+)
+
+// SchemaDiff compares a version's response with the schema its test case
+// declares for it, catching fields the schema doesn't document and required
+// fields the response lacks
+type SchemaDiff struct {
+	Version string `json:"version"`
+	Schema  string `json:"schema"`
+
+	// Undocumented and Missing are paths such as "items[].discount"
+	Undocumented []string `json:"undocumented,omitempty"`
+	Missing      []string `json:"missing,omitempty"`
+
+	// Error is set when the comparison could not be made (failed execution,
+	// unreadable schema or a non-JSON response)
+	Error string `json:"error,omitempty"`
+}
+
+// HasDrift reports whether the response departs from the schema
+func (d SchemaDiff) HasDrift() bool {
+	return len(d.Undocumented) > 0 || len(d.Missing) > 0
+}
+
+// compareAgainstSchema compares a response body with the schema at
+// schemaPath (optionally with a "#/..." pointer to a component inside it)
+func compareAgainstSchema(response []byte, schemaPath string) SchemaDiff {
+	diff := SchemaDiff{Schema: schemaPath}
+
+	s, err := schema.Load(schemaPath)
+	if err != nil {
+		diff.Error = err.Error()
+		return diff
+	}
+	var doc interface{}
+	if err := json.Unmarshal(response, &doc); err != nil {
+		diff.Error = fmt.Sprintf("response is not valid JSON: %v", err)
+		return diff
+	}
+
+	drift := s.Compare(doc)
+	diff.Undocumented, diff.Missing = drift.Undocumented, drift.Missing
+	return diff
+}
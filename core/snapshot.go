@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Snapshot change kinds. Text diffs and status changes have no JSON path and
+// are recorded under the pseudo-paths SnapshotBodyPath and SnapshotStatusPath.
+const (
+	SnapshotAdded   = "added"
+	SnapshotRemoved = "removed"
+	SnapshotChanged = "changed"
+	SnapshotText    = "text"
+	SnapshotStatus  = "status"
+
+	SnapshotBodyPath   = "(body)"
+	SnapshotStatusPath = "(status)"
+)
+
+// Snapshot records the changes of a run in a form meant to be committed and
+// reviewed: one entry per test case, version pair and change path, sorted so
+// that rewriting it only shows what moved
+type Snapshot struct {
+	// TestCases lists the test cases the snapshot covers, so that a run of
+	// a subset doesn't report the others' changes as resolved
+	TestCases []string         `json:"test_cases"`
+	Changes   []SnapshotChange `json:"changes"`
+}
+
+// SnapshotChange is one changed path of a version pair
+type SnapshotChange struct {
+	TestCase string `json:"test_case"`
+	VersionA string `json:"version_a"`
+	VersionB string `json:"version_b"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+}
+
+// key identifies a change across runs by test case, version pair and path
+func (c SnapshotChange) key() string {
+	return c.TestCase + "\x00" + c.VersionA + "\x00" + c.VersionB + "\x00" + c.Path
+}
+
+// SnapshotDiff holds the changes that appeared or disappeared since a baseline
+type SnapshotDiff struct {
+	New      []SnapshotChange `json:"new,omitempty"`
+	Resolved []SnapshotChange `json:"resolved,omitempty"`
+}
+
+// HasChanges reports whether the run drifted from the baseline
+func (d SnapshotDiff) HasChanges() bool {
+	return len(d.New) > 0 || len(d.Resolved) > 0
+}
+
+// NewSnapshot records the structured changes of a run. Failed comparisons
+// and pairs whose changes are all expected (expected_diffs) are left out.
+func NewSnapshot(result *RunResult) Snapshot {
+	snapshot := Snapshot{TestCases: []string{}, Changes: []SnapshotChange{}}
+	for _, cmdRes := range result.CommandResults {
+		snapshot.TestCases = append(snapshot.TestCases, cmdRes.TestCaseName)
+		for _, d := range cmdRes.Diffs {
+			if d.Error != "" || d.DiffResult == nil || d.Expected {
+				continue
+			}
+			add := func(path, kind string) {
+				snapshot.Changes = append(snapshot.Changes, SnapshotChange{
+					TestCase: cmdRes.TestCaseName,
+					VersionA: d.VersionA,
+					VersionB: d.VersionB,
+					Path:     path,
+					Kind:     kind,
+				})
+			}
+			if d.StatusChanged {
+				add(SnapshotStatusPath, SnapshotStatus)
+			}
+			if changes := d.DiffResult.Changes; changes != nil {
+				for _, path := range changes.Added {
+					add(path, SnapshotAdded)
+				}
+				for _, path := range changes.Removed {
+					add(path, SnapshotRemoved)
+				}
+				for _, path := range changes.Changed {
+					add(path, SnapshotChanged)
+				}
+			} else if !d.DiffResult.IsJSON && d.DiffResult.TextDiff != "" {
+				add(SnapshotBodyPath, SnapshotText)
+			}
+		}
+	}
+	snapshot.sort()
+	return snapshot
+}
+
+// sort orders test cases and changes for stable files
+func (s *Snapshot) sort() {
+	sort.Strings(s.TestCases)
+	sort.Slice(s.Changes, func(i, j int) bool {
+		return s.Changes[i].key() < s.Changes[j].key()
+	})
+}
+
+// covers reports whether the snapshot includes a test case
+func (s Snapshot) covers(testCase string) bool {
+	i := sort.SearchStrings(s.TestCases, testCase)
+	return i < len(s.TestCases) && s.TestCases[i] == testCase
+}
+
+// CompareSnapshots returns the changes of current that are not in baseline
+// (new) and those of baseline that current no longer has (resolved). Only
+// test cases that current covers can have resolved changes.
+func CompareSnapshots(baseline, current Snapshot) SnapshotDiff {
+	before := make(map[string]bool, len(baseline.Changes))
+	for _, c := range baseline.Changes {
+		before[c.key()] = true
+	}
+	now := make(map[string]bool, len(current.Changes))
+	for _, c := range current.Changes {
+		now[c.key()] = true
+	}
+
+	var diff SnapshotDiff
+	for _, c := range current.Changes {
+		if !before[c.key()] {
+			diff.New = append(diff.New, c)
+		}
+	}
+	for _, c := range baseline.Changes {
+		if !now[c.key()] && current.covers(c.TestCase) {
+			diff.Resolved = append(diff.Resolved, c)
+		}
+	}
+	return diff
+}
+
+// MergeSnapshots replaces the test cases of baseline that current covers
+// with current's changes, keeping the rest of baseline as is
+func MergeSnapshots(baseline, current Snapshot) Snapshot {
+	merged := Snapshot{
+		TestCases: append([]string{}, current.TestCases...),
+		Changes:   append([]SnapshotChange{}, current.Changes...),
+	}
+	for _, tc := range baseline.TestCases {
+		if !current.covers(tc) {
+			merged.TestCases = append(merged.TestCases, tc)
+		}
+	}
+	for _, c := range baseline.Changes {
+		if !current.covers(c.TestCase) {
+			merged.Changes = append(merged.Changes, c)
+		}
+	}
+	merged.sort()
+	return merged
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot
+func LoadSnapshot(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("invalid baseline file %s: %w", path, err)
+	}
+	snapshot.sort()
+	return snapshot, nil
+}
+
+// SaveSnapshot writes a snapshot as indented JSON
+func SaveSnapshot(path string, snapshot Snapshot) error {
+	snapshot.sort()
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline file: %w", err)
+	}
+	return nil
+}
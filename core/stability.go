@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/executor"
+)
+
+// Stability reports how a version's response varied when its command was
+// run repeatedly (config "repeat"). Fields that change between runs of the
+// same version are nondeterministic and candidates for ignore_paths.
+type Stability struct {
+	Version string `json:"version"`
+
+	// Runs counts the successful runs that were compared, including the
+	// one whose response was stored
+	Runs int `json:"runs"`
+
+	// Varied is set when any run's response differed from the previous one.
+	// UnstableFields lists the JSON paths that changed, with array indexes
+	// as "[]" so they can be copied into ignore_paths.
+	Varied         bool     `json:"varied"`
+	UnstableFields []string `json:"unstable_fields,omitempty"`
+
+	// Error is the first failure among the repeated runs
+	Error string `json:"error,omitempty"`
+}
+
+// measureStability runs a command repeat-1 more times after the run that
+// produced first, diffing each response against the previous successful one
+func measureStability(ctx context.Context, execute executor.Executor, comparer comparator.Comparer,
+	opts executor.ExecuteOptions, first []byte, repeat int, compareOpts comparator.CompareOptions) Stability {
+	stability := Stability{Version: opts.Version, Runs: 1}
+	opts.ResponseSink = nil
+
+	unstable := make(map[string]bool)
+	previous := first
+	for run := 1; run < repeat; run++ {
+		if ctx.Err() != nil {
+			stability.Error = fmt.Sprintf("operation cancelled: %v", ctx.Err())
+			break
+		}
+		res, err := execute.Execute(opts)
+		if err != nil {
+			if stability.Error == "" {
+				stability.Error = fmt.Sprintf("run %d: %v", run+1, err)
+			}
+			continue
+		}
+		stability.Runs++
+
+		diff, err := comparer.Compare(previous, res.Response, "previous", "current", compareOpts)
+		if err != nil {
+			if stability.Error == "" {
+				stability.Error = fmt.Sprintf("run %d: %v", run+1, err)
+			}
+			continue
+		}
+		if diff.IsJSON && diff.Summary != comparator.NoChangesSummary || !diff.IsJSON && diff.TextDiff != "" {
+			stability.Varied = true
+		}
+		if diff.Changes != nil {
+			for _, paths := range [][]string{diff.Changes.Added, diff.Changes.Removed, diff.Changes.Changed} {
+				for _, path := range paths {
+					unstable[comparator.WildcardPath(path)] = true
+				}
+			}
+		}
+		previous = res.Response
+	}
+
+	for path := range unstable {
+		stability.UnstableFields = append(stability.UnstableFields, path)
+	}
+	sort.Strings(stability.UnstableFields)
+	return stability
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/storage"
+)
+
+// Store diff statuses
+const (
+	StoreDiffAdded     = "added"     // Response only exists in the second store
+	StoreDiffRemoved   = "removed"   // Response only exists in the first store
+	StoreDiffChanged   = "changed"   // Response exists in both and differs
+	StoreDiffUnchanged = "unchanged" // Response exists in both and is equivalent
+)
+
+// StoreDiffEntry is the comparison of one (command, version) response across two stores
+type StoreDiffEntry struct {
+	CommandHash string                 `json:"command_hash"`
+	Command     string                 `json:"command"`
+	Version     string                 `json:"version"`
+	Status      string                 `json:"status"`
+	DiffResult  *comparator.DiffResult `json:"diff_result,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// StoreDiffResult holds the bulk comparison of two response directories
+type StoreDiffResult struct {
+	Entries   []StoreDiffEntry `json:"entries"`
+	Added     int              `json:"added"`
+	Removed   int              `json:"removed"`
+	Changed   int              `json:"changed"`
+	Unchanged int              `json:"unchanged"`
+}
+
+// DiffStores compares the latest stored responses of two storage directories,
+// pairing executions by command hash and version.
+func DiffStores(dirA, dirB string, opts comparator.CompareOptions) (*StoreDiffResult, error) {
+	storeA, err := storage.OpenStore(dirA)
+	if err != nil {
+		return nil, err
+	}
+	storeB, err := storage.OpenStore(dirB)
+	if err != nil {
+		return nil, err
+	}
+
+	latestA := storeA.LatestResponses()
+	latestB := storeB.LatestResponses()
+
+	// Union of keys, sorted for stable output
+	keySet := make(map[storage.ResponseKey]bool)
+	for k := range latestA {
+		keySet[k] = true
+	}
+	for k := range latestB {
+		keySet[k] = true
+	}
+	keys := make([]storage.ResponseKey, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].CommandHash != keys[j].CommandHash {
+			return keys[i].CommandHash < keys[j].CommandHash
+		}
+		return keys[i].Version < keys[j].Version
+	})
+
+	result := &StoreDiffResult{}
+	for _, key := range keys {
+		recA, inA := latestA[key]
+		recB, inB := latestB[key]
+
+		entry := StoreDiffEntry{CommandHash: key.CommandHash, Version: key.Version}
+		if inA {
+			entry.Command = storeA.CommandFor(key.CommandHash)
+		} else {
+			entry.Command = storeB.CommandFor(key.CommandHash)
+		}
+
+		switch {
+		case !inA:
+			entry.Status = StoreDiffAdded
+			result.Added++
+		case !inB:
+			entry.Status = StoreDiffRemoved
+			result.Removed++
+		default:
+			fileA := storeA.GetResponsePath(recA.ResponseFile)
+			fileB := storeB.GetResponsePath(recB.ResponseFile)
+			diff, changed, err := compareStoredFiles(fileA, fileB, opts)
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			entry.DiffResult = diff
+			if changed {
+				entry.Status = StoreDiffChanged
+				result.Changed++
+			} else {
+				entry.Status = StoreDiffUnchanged
+				result.Unchanged++
+			}
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+// compareStoredFiles compares two stored response files. Byte-identical files
+// skip the comparator entirely. Read errors count as a change.
+func compareStoredFiles(fileA, fileB string, opts comparator.CompareOptions) (*comparator.DiffResult, bool, error) {
+	b1, err := storage.ReadResponse(fileA)
+	if err != nil {
+		return nil, true, fmt.Errorf("read %s: %w", fileA, err)
+	}
+	b2, err := storage.ReadResponse(fileB)
+	if err != nil {
+		return nil, true, fmt.Errorf("read %s: %w", fileB, err)
+	}
+	if bytes.Equal(b1, b2) {
+		return nil, false, nil
+	}
+
+	diff, err := comparator.CompareWithOptions(b1, b2, fileA, fileB, opts)
+	if err != nil {
+		return nil, true, err
+	}
+	return diff, diff.Summary != comparator.NoChangesSummary, nil
+}
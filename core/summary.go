@@ -0,0 +1,111 @@
+package core
+
+import (
+	"sort"
+
+	"api_diff_checker/comparator"
+)
+
+// TopChangedFields is how many of the most-changed fields a RunSummary lists
+const TopChangedFields = 10
+
+// RunSummary aggregates a run for a quick verdict before reading the
+// per-pair diffs
+type RunSummary struct {
+	TestCases int `json:"test_cases"`
+
+	// Changed counts test cases with at least one differing version pair and
+	// Failed those with a comparison that failed; Clean counts the rest
+	Changed int `json:"changed"`
+	Failed  int `json:"failed"`
+	Clean   int `json:"clean"`
+
+	// ExecutionErrors counts failed executions, TimedOut the ones among
+	// them that hit the timeout
+	ExecutionErrors int `json:"execution_errors"`
+	TimedOut        int `json:"timed_out"`
+
+	// Expected counts version pairs whose changes are all acknowledged by
+	// expected_diffs; they don't make a test case changed
+	Expected int `json:"expected,omitempty"`
+
+	// TopFields lists the fields that changed in the most version pairs,
+	// with array indexes as "[]" so one field in many elements counts once
+	TopFields []FieldCount `json:"top_fields,omitempty"`
+}
+
+// FieldCount is how many version pairs a field changed in
+type FieldCount struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// IsClean reports whether no test case changed or failed and every
+// execution succeeded
+func (s RunSummary) IsClean() bool {
+	return s.Changed == 0 && s.Failed == 0 && s.ExecutionErrors == 0
+}
+
+// Summarize aggregates the results of a run, counting changed fields from
+// the structured changes of each diff
+func Summarize(result *RunResult) RunSummary {
+	summary := RunSummary{TestCases: len(result.CommandResults)}
+	fieldCounts := make(map[string]int)
+
+	for _, cmdRes := range result.CommandResults {
+		diffs, errs := countOutcomes(cmdRes)
+		switch {
+		case errs > 0:
+			summary.Failed++
+		case diffs > 0:
+			summary.Changed++
+		default:
+			summary.Clean++
+		}
+
+		for _, info := range cmdRes.ExecInfo {
+			if info.Error != "" {
+				summary.ExecutionErrors++
+			}
+			if info.TimedOut {
+				summary.TimedOut++
+			}
+		}
+
+		for _, d := range cmdRes.Diffs {
+			if d.Expected {
+				summary.Expected++
+				continue
+			}
+			if d.DiffResult == nil || d.DiffResult.Changes == nil {
+				continue
+			}
+			changes := d.DiffResult.Changes
+			seen := make(map[string]bool)
+			for _, paths := range [][]string{changes.Added, changes.Removed, changes.Changed} {
+				for _, path := range paths {
+					field := comparator.WildcardPath(path)
+					if !seen[field] {
+						seen[field] = true
+						fieldCounts[field]++
+					}
+				}
+			}
+		}
+	}
+
+	for path, count := range fieldCounts {
+		summary.TopFields = append(summary.TopFields, FieldCount{Path: path, Count: count})
+	}
+	sort.Slice(summary.TopFields, func(i, j int) bool {
+		a, b := summary.TopFields[i], summary.TopFields[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Path < b.Path
+	})
+	if len(summary.TopFields) > TopChangedFields {
+		summary.TopFields = summary.TopFields[:TopChangedFields]
+	}
+	return summary
+}
@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// BodyPlaceholder is replaced with the built request body in commands
+const BodyPlaceholder = "{{BODY}}"
+
+// BuildBody applies field renames to a shared JSON body. Keys of renames are
+// dotted paths to the field (e.g. "userId" or "user.firstName") and values are
+// the new field name. Arrays along the path apply the rename to every element.
+// Renames for fields that don't exist are ignored. Every path refers to the
+// original body, so {"user": "account", "user.name": "fullName"} renames both
+// and {"a": "b", "b": "c"} moves a to b and b to c.
+func BuildBody(base []byte, renames map[string]string) ([]byte, error) {
+	if len(renames) == 0 {
+		return base, nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(base, &body); err != nil {
+		return nil, fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+
+	// Deeper paths go first so a renamed parent is still found under its
+	// original name. Fields at the same depth are all detached before any is
+	// reattached, so one rename can't take a field another rename targets.
+	paths := make([][]string, 0, len(renames))
+	for path := range renames {
+		paths = append(paths, strings.Split(path, "."))
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) > len(paths[j])
+		}
+		return strings.Join(paths[i], ".") < strings.Join(paths[j], ".")
+	})
+	for start := 0; start < len(paths); {
+		end := start
+		var moves []fieldMove
+		for ; end < len(paths) && len(paths[end]) == len(paths[start]); end++ {
+			moves = detachField(body, paths[end], renames[strings.Join(paths[end], ".")], moves)
+		}
+		for _, m := range moves {
+			m.parent[m.name] = m.value
+		}
+		start = end
+	}
+
+	out, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	return out, nil
+}
+
+// fieldMove is a field removed from parent that is to be added back as name
+type fieldMove struct {
+	parent map[string]interface{}
+	name   string
+	value  interface{}
+}
+
+// detachField removes the field at path within v, appending a move that
+// reattaches it as newName
+func detachField(v interface{}, path []string, newName string, moves []fieldMove) []fieldMove {
+	switch val := v.(type) {
+	case []interface{}:
+		for _, elem := range val {
+			moves = detachField(elem, path, newName, moves)
+		}
+	case map[string]interface{}:
+		child, ok := val[path[0]]
+		if !ok {
+			return moves
+		}
+		if len(path) == 1 {
+			delete(val, path[0])
+			return append(moves, fieldMove{parent: val, name: newName, value: child})
+		}
+		return detachField(child, path[1:], newName, moves)
+	}
+	return moves
+}
+
+// injectBody places the body into the command arguments. Every {{BODY}}
+// occurrence is replaced; if there is none the body is appended as
+// --data-raw along with a JSON Content-Type header unless one is set, or
+// for grpcurl passed as the -d request message.
+func injectBody(args []string, body []byte) []string {
+	replaced := false
+	result := make([]string, len(args))
+	for i, arg := range args {
+		if strings.Contains(arg, BodyPlaceholder) {
+			arg = strings.ReplaceAll(arg, BodyPlaceholder, string(body))
+			replaced = true
+		}
+		result[i] = arg
+	}
+	if replaced {
+		return result
+	}
+	if isGrpcurl(result) {
+		return insertFlags(result, "-d", string(body))
+	}
+
+	if !hasHeader(result, "Content-Type") {
+		result = append(result, "-H", "Content-Type: application/json")
+	}
+	return append(result, "--data-raw", string(body))
+}
+
+// hasHeader reports whether the arguments already set the named header
+func hasHeader(args []string, name string) bool {
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] != "-H" && args[i] != "--header" {
+			continue
+		}
+		if k, _, ok := strings.Cut(args[i+1], ":"); ok && http.CanonicalHeaderKey(strings.TrimSpace(k)) == http.CanonicalHeaderKey(name) {
+			return true
+		}
+	}
+	return false
+}
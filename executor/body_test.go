@@ -0,0 +1,51 @@
+package executor
+
+import "testing"
+
+func TestBuildBodyOverlappingRenames(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		renames map[string]string
+		want    string
+	}{
+		{
+			name:    "parent and child",
+			base:    `{"user": {"name": "alice", "age": 3}}`,
+			renames: map[string]string{"user": "account", "user.name": "fullName"},
+			want:    `{"account":{"age":3,"fullName":"alice"}}`,
+		},
+		{
+			name:    "chained",
+			base:    `{"a": 1, "b": 2}`,
+			renames: map[string]string{"a": "b", "b": "c"},
+			want:    `{"b":1,"c":2}`,
+		},
+		{
+			name:    "swap",
+			base:    `{"a": 1, "b": 2}`,
+			renames: map[string]string{"a": "b", "b": "a"},
+			want:    `{"a":2,"b":1}`,
+		},
+		{
+			name:    "inside arrays",
+			base:    `{"items": [{"id": 1}, {"id": 2}]}`,
+			renames: map[string]string{"items": "rows", "items.id": "key"},
+			want:    `{"rows":[{"key":1},{"key":2}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order varies, so repeat to catch order dependence
+			for i := 0; i < 20; i++ {
+				got, err := BuildBody([]byte(tt.base), tt.renames)
+				if err != nil {
+					t.Fatalf("BuildBody: %v", err)
+				}
+				if string(got) != tt.want {
+					t.Fatalf("BuildBody = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileDataFlags are the data options whose "@path" argument makes curl read
+// the body from a file (--data-raw sends "@" literally)
+var fileDataFlags = map[string]bool{
+	"-d":            true,
+	"--data":        true,
+	"--data-binary": true,
+	"--data-ascii":  true,
+	"--json":        true,
+}
+
+// resolveDataFiles rewrites "@path" arguments of curl data options so
+// relative paths are taken from baseDir (the config file's directory) rather
+// than the working directory, and fails clearly when a file is missing.
+// "@-" (stdin) and non-curl commands are left unchanged.
+func resolveDataFiles(args []string, baseDir string) ([]string, error) {
+	if !isCurl(args) {
+		return args, nil
+	}
+
+	out := make([]string, len(args))
+	copy(out, args)
+	for i := 1; i < len(out); i++ {
+		name, value, inline := out[i], "", false
+		if strings.HasPrefix(name, "--") {
+			if idx := strings.Index(name, "="); idx > 0 {
+				name, value, inline = name[:idx], name[idx+1:], true
+			}
+		}
+		if !fileDataFlags[name] {
+			continue
+		}
+		if !inline {
+			if i+1 >= len(out) {
+				break
+			}
+			i++
+			value = out[i]
+		}
+		if !strings.HasPrefix(value, "@") || value == "@-" {
+			continue
+		}
+
+		path := value[1:]
+		if baseDir != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, fmt.Errorf("request body file not found: %s", path)
+		} else if err != nil {
+			return nil, fmt.Errorf("request body file %s: %w", path, err)
+		}
+		if inline {
+			out[i] = name + "=@" + path
+		} else {
+			out[i] = "@" + path
+		}
+	}
+	return out, nil
+}
+
+// readDataFile loads a body referenced with "@path", applying curl's rule
+// that -d/--data/--data-ascii strip carriage returns and newlines while
+// --data-binary and --json send the file as is
+func readDataFile(flag, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("request body file %s: %w", path, err)
+	}
+	if flag == "--data-binary" || flag == "--json" {
+		return string(data), nil
+	}
+	return strings.NewReplacer("\r", "", "\n", "").Replace(string(data)), nil
+}
@@ -0,0 +1,26 @@
+package executor
+
+// Executor runs one command for one version. The engine executes every
+// command through one, so tests can substitute a fake and other transports
+// (gRPC, GraphQL clients) can be plugged in without going through curl.
+type Executor interface {
+	Execute(opts ExecuteOptions) (*ExecutionResult, error)
+}
+
+// ExecutorFunc adapts a function to the Executor interface
+type ExecutorFunc func(opts ExecuteOptions) (*ExecutionResult, error)
+
+func (f ExecutorFunc) Execute(opts ExecuteOptions) (*ExecutionResult, error) {
+	return f(opts)
+}
+
+// CurlExecutor runs commands as processes (normally curl) and captures their
+// output. It is the default Executor.
+type CurlExecutor struct{}
+
+// NativeExecutor sends curl-style commands with net/http, needing no curl
+// binary (engine "native")
+type NativeExecutor struct{}
+
+// DefaultExecutor is the Executor used when none is configured
+var DefaultExecutor Executor = CurlExecutor{}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// DefaultFilterTimeout bounds how long a filter command may run
+const DefaultFilterTimeout = 10 * time.Second
+
+// RunFilter runs a command with input on stdin and returns its stdout. The
+// command is killed after timeout (DefaultFilterTimeout if 0); timeouts and
+// non-zero exits are returned as errors that include stderr.
+func RunFilter(command string, input []byte, timeout time.Duration) ([]byte, error) {
+	if timeout <= 0 {
+		timeout = DefaultFilterTimeout
+	}
+
+	args, err := shellwords.Parse(normalizeCommand(command))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse command: %w", err)
+	}
+	if len(args) == 0 {
+		return nil, errEmptyCommand
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("command timed out after %s", timeout)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("execution failed: %v", err)
+		if errOut := strings.TrimSpace(stderr.String()); errOut != "" {
+			msg += fmt.Sprintf(" | stderr: %s", errOut)
+		}
+		return nil, fmt.Errorf("%s", msg)
+	}
+	return stdout.Bytes(), nil
+}
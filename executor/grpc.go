@@ -0,0 +1,28 @@
+package executor
+
+import "net/url"
+
+// isGrpcurl reports whether a command invokes grpcurl, whose flags must
+// precede the address and method
+func isGrpcurl(args []string) bool {
+	return toolName(args) == "grpcurl"
+}
+
+// grpcAddress returns the host:port of a base URL such as
+// "grpc://users.internal:443", which is what grpcurl expects. Base URLs
+// without a host are returned unchanged.
+func grpcAddress(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return baseURL
+}
+
+// insertFlags adds flags right after the binary name, before any positional
+// arguments
+func insertFlags(args []string, flags ...string) []string {
+	out := make([]string, 0, len(args)+len(flags))
+	out = append(out, args[0])
+	out = append(out, flags...)
+	return append(out, args[1:]...)
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// injectHeaderDump asks curl to write the response headers to a temporary
+// file. Non-curl commands and commands that already dump or include headers
+// are left unchanged, in which case the returned path is empty. The caller
+// removes the file.
+func injectHeaderDump(args []string) ([]string, string, error) {
+	if !isCurl(args) ||
+		hasCurlFlag(args, 'D', "--dump-header") || hasCurlFlag(args, 'i', "--include") {
+		return args, "", nil
+	}
+
+	f, err := os.CreateTemp("", "api_diff_checker_headers_*")
+	if err != nil {
+		return args, "", err
+	}
+	path := f.Name()
+	f.Close()
+
+	out := make([]string, len(args), len(args)+2)
+	copy(out, args)
+	return append(out, "-D", path), path, nil
+}
+
+// readHeaderDump reads a header file written by curl -D
+func readHeaderDump(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return parseHeaderDump(string(data))
+}
+
+// parseHeaderDump parses the output of curl -D. When redirects were followed
+// the dump holds one block per response; only the last one is returned.
+// Repeated headers are joined with ", ".
+func parseHeaderDump(dump string) map[string]string {
+	dump = strings.ReplaceAll(dump, "\r\n", "\n")
+
+	var last string
+	for _, block := range strings.Split(dump, "\n\n") {
+		if strings.HasPrefix(strings.TrimSpace(block), "HTTP/") {
+			last = strings.TrimSpace(block)
+		}
+	}
+	if last == "" {
+		return nil
+	}
+
+	headers := make(map[string]string)
+	for _, line := range strings.Split(last, "\n")[1:] {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if existing, seen := headers[name]; seen {
+			value = existing + ", " + value
+		}
+		headers[name] = value
+	}
+	return headers
+}
+
+// flattenHeaders converts net/http headers to a map, joining repeated values with ", "
+func flattenHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for name, values := range h {
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// DefaultMaxResponseBytes caps how much of a response body is kept in
+// memory when ExecuteOptions.MaxResponseBytes is 0
+const DefaultMaxResponseBytes = 64 << 20
+
+// cappedTailSize is how much of the end of curl's output is kept past the
+// limit, so the status written by --write-out can still be read
+const cappedTailSize = 4096
+
+// cappedBuffer is an io.Writer that keeps the first limit bytes written to
+// it and the last cappedTailSize bytes, discarding the rest, so a runaway
+// response can't exhaust memory
+type cappedBuffer struct {
+	limit     int64
+	head      bytes.Buffer
+	tail      []byte
+	truncated bool
+}
+
+func newCappedBuffer(limit int64) *cappedBuffer {
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	return &cappedBuffer{limit: limit}
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := c.limit - int64(c.head.Len()); room > 0 {
+		take := p
+		if int64(len(take)) > room {
+			take = take[:room]
+		}
+		c.head.Write(take)
+		p = p[len(take):]
+	}
+	if len(p) > 0 {
+		c.truncated = true
+		c.tail = append(c.tail, p...)
+		if len(c.tail) > cappedTailSize {
+			c.tail = append(c.tail[:0], c.tail[len(c.tail)-cappedTailSize:]...)
+		}
+	}
+	return n, nil
+}
+
+// output returns the kept body and, when the limit was exceeded, the tail
+// of the discarded output (nil otherwise)
+func (c *cappedBuffer) output() ([]byte, []byte) {
+	if !c.truncated {
+		return c.head.Bytes(), nil
+	}
+	return c.head.Bytes(), c.tail
+}
+
+// deadlineBody ends a response body cleanly when ctx's deadline cuts it off,
+// so what arrived before the timeout can still be stored. Partial reports
+// whether that happened after at least one byte was read.
+type deadlineBody struct {
+	ctx     context.Context
+	r       io.Reader
+	read    int64
+	partial bool
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && b.ctx.Err() == context.DeadlineExceeded && b.read > 0 {
+		b.partial = true
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// Partial reports whether the body was cut short by the deadline
+func (b *deadlineBody) Partial() bool {
+	return b.partial
+}
+
+// readCapped reads at most limit bytes of r (DefaultMaxResponseBytes when
+// limit is 0), reporting whether more was available
+func readCapped(r io.Reader, limit int64) ([]byte, bool, error) {
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if int64(len(data)) > limit {
+		return data[:limit], true, err
+	}
+	return data, false, err
+}
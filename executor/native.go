@@ -0,0 +1,256 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// nativeIgnoredFlags are curl options that have no meaningful effect when the
+// request is sent natively (output formatting, timeouts handled by context)
+var nativeIgnoredFlags = map[string]bool{
+	"-s":                true,
+	"--silent":          true,
+	"-S":                true,
+	"--show-error":      true,
+	"-v":                true,
+	"--verbose":         true,
+	"-g":                true,
+	"--globoff":         true,
+	"--compressed":      true,
+	"--http1.1":         true,
+	"-N":                true,
+	"--no-buffer":       true,
+	"-m":                true,
+	"--max-time":        true,
+	"--connect-timeout": true,
+}
+
+// nativeFlags are the curl behaviors emulated by native execution
+type nativeFlags struct {
+	insecure       bool // -k
+	followRedirect bool // -L
+	failOnError    bool // -f
+}
+
+// ExecuteNative runs a curl-style command with the NativeExecutor
+func ExecuteNative(opts ExecuteOptions) (*ExecutionResult, error) {
+	return NativeExecutor{}.Execute(opts)
+}
+
+// Execute runs a curl-style command with net/http instead of the curl
+// binary. The command is parsed (URL, -X, -H, -d/--data and friends, -u, -G)
+// into an http.Request; unsupported flags are reported as errors. It takes the
+// same options as CurlExecutor, and honors ResponseSink.
+func (NativeExecutor) Execute(opts ExecuteOptions) (*ExecutionResult, error) {
+	commandTmpl, version, baseURL, timeout := opts.Command, opts.Version, opts.BaseURL, opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	if delay := injectedDelay(opts); delay > 0 {
+		time.Sleep(delay)
+	}
+
+	finalCmdStr, args, err := ResolveArgs(commandTmpl, baseURL, opts)
+	failed := func(err error) (*ExecutionResult, error) {
+		return &ExecutionResult{
+			Command:   finalCmdStr,
+			Version:   version,
+			Timestamp: time.Now(),
+			Error:     err.Error(),
+		}, err
+	}
+	if err != nil {
+		return failed(err)
+	}
+	if !isCurl(args) {
+		return failed(fmt.Errorf("native mode only supports curl commands, not '%s' - use engine \"curl\" for this command", args[0]))
+	}
+
+	spec, err := parseCurlArgs(args[1:])
+	if err != nil {
+		return failed(fmt.Errorf("native mode: %w", err))
+	}
+	flags, err := nativeFlagsFor(spec)
+	if err != nil {
+		return failed(err)
+	}
+
+	ctx, cancel := context.WithTimeout(opts.parentContext(), timeout)
+	defer cancel()
+
+	req, err := buildNativeRequest(ctx, spec)
+	if err != nil {
+		return failed(err)
+	}
+
+	if opts.InsecureSkipVerify {
+		flags.insecure = true
+	}
+
+	client := &http.Client{Transport: http.DefaultTransport}
+	if flags.insecure || opts.Proxy != "" {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if flags.insecure {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		if opts.Proxy != "" {
+			proxyURL, err := url.Parse(opts.Proxy)
+			if err != nil {
+				return failed(fmt.Errorf("invalid proxy: %w", err))
+			}
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+		client.Transport = transport
+	}
+	client.CheckRedirect = redirectPolicy(flags.followRedirect || opts.FollowRedirects, opts.MaxRedirects)
+
+	start := time.Now()
+	result := &ExecutionResult{
+		Command:   finalCmdStr,
+		Version:   version,
+		Timestamp: start,
+	}
+
+	resp, err := client.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		result.StatusCode = resp.StatusCode
+		result.ContentType = resp.Header.Get("Content-Type")
+		if opts.CaptureHeaders {
+			result.Headers = flattenHeaders(resp.Header)
+		}
+		body := &deadlineBody{ctx: ctx, r: resp.Body}
+		switch {
+		case flags.failOnError && resp.StatusCode >= 400:
+			// The body is discarded below
+		case opts.ResponseSink != nil:
+			result.ResponseFile, err = opts.ResponseSink(result, body)
+		default:
+			result.Response, result.Truncated, err = readCapped(body, opts.MaxResponseBytes)
+		}
+		result.Partial = body.Partial() && err == nil
+	}
+	result.Duration = time.Since(start).String()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		if !result.Partial {
+			result.Response, result.ResponseFile = nil, ""
+		}
+		result.Error = fmt.Sprintf("command timed out after %s", timeout)
+		return result, ctx.Err()
+	}
+	if ctx.Err() == context.Canceled {
+		result.Response, result.ResponseFile = nil, ""
+		result.Error = errCancelled
+		return result, ctx.Err()
+	}
+	if err != nil {
+		result.Response, result.ResponseFile = nil, ""
+		result.Error = fmt.Sprintf("execution failed: %v", err)
+		return result, err
+	}
+
+	result.FinalURL = resp.Request.URL.String()
+	for req := resp.Request; req.Response != nil; req = req.Response.Request {
+		result.Redirects++
+	}
+	if len(resp.Trailer) > 0 {
+		result.Trailers = make(map[string]string, len(resp.Trailer))
+		for name := range resp.Trailer {
+			result.Trailers[name] = resp.Trailer.Get(name)
+		}
+	}
+
+	if flags.failOnError && resp.StatusCode >= 400 {
+		// Mirror curl --fail: no body, error on HTTP errors
+		err := fmt.Errorf("requested URL returned error: %d", resp.StatusCode)
+		result.Response = nil
+		result.Error = fmt.Sprintf("execution failed: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// nativeFlagsFor checks the curl flags of a parsed command can be honored
+// natively and returns the behaviors they request
+func nativeFlagsFor(spec *RequestSpec) (nativeFlags, error) {
+	var flags nativeFlags
+	var unsupported []string
+	for _, flag := range spec.Unsupported {
+		switch {
+		case nativeIgnoredFlags[flag]:
+		case flag == "-k" || flag == "--insecure":
+			flags.insecure = true
+		case flag == "-L" || flag == "--location":
+			flags.followRedirect = true
+		case flag == "-f" || flag == "--fail":
+			flags.failOnError = true
+		default:
+			unsupported = append(unsupported, flag)
+		}
+	}
+	if spec.bodyFromFile {
+		unsupported = append(unsupported, "@- or --data-urlencode @file data")
+	}
+	if len(unsupported) > 0 {
+		return flags, fmt.Errorf("native mode does not support %s - use engine \"curl\" for this command",
+			strings.Join(unsupported, ", "))
+	}
+	return flags, nil
+}
+
+// buildNativeRequest converts a parsed command into an http.Request,
+// applying curl's default headers for data and Accept
+func buildNativeRequest(ctx context.Context, spec *RequestSpec) (*http.Request, error) {
+	var body io.Reader
+	if spec.rawBody != "" {
+		body = strings.NewReader(spec.rawBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, spec.Method, spec.target, body)
+	if err != nil {
+		return nil, fmt.Errorf("native mode: invalid request: %w", err)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, errors.New("native mode only supports http and https URLs")
+	}
+
+	req.Header.Set("Accept", "*/*")
+	if spec.rawBody != "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for name, value := range spec.Headers {
+		switch {
+		case name == "Authorization" && spec.basicAuth != "" && value == redactedBasicAuth:
+			// Set properly encoded below
+		case name == "Host":
+			req.Host = value
+		default:
+			req.Header.Set(name, value)
+		}
+	}
+	if spec.basicAuth != "" {
+		user, password, _ := strings.Cut(spec.basicAuth, ":")
+		req.SetBasicAuth(user, password)
+	}
+	return req, nil
+}
+
+// ExecuteNativeWithOptions runs ExecuteNative with the command inputs given
+// separately from the other options.
+//
+// Deprecated: set Command, Version, BaseURL and Timeout in opts and call ExecuteNative.
+func ExecuteNativeWithOptions(commandTmpl string, version string, baseURL string, timeout time.Duration, opts ExecuteOptions) (*ExecutionResult, error) {
+	opts.Command, opts.Version, opts.BaseURL, opts.Timeout = commandTmpl, version, baseURL, timeout
+	return ExecuteNative(opts)
+}
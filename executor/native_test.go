@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteNativeStopsWhenContextCancelled(t *testing.T) {
+	srv := slowServer(t, 10*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	res, err := ExecuteNative(ExecuteOptions{
+		Command: "curl -s {{BASE_URL}}/slow",
+		Version: "v1",
+		BaseURL: srv.URL,
+		Timeout: 10 * time.Second,
+		Context: ctx,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled execution took %s", elapsed)
+	}
+	if res.Error != errCancelled || res.TimedOut {
+		t.Errorf("result error = %q, timed out = %v", res.Error, res.TimedOut)
+	}
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// echoServer answers with the method, path and X-Tenant header it received
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"method":%q,"path":%q,"tenant":%q}`, r.Method, r.URL.Path, r.Header.Get("X-Tenant"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLegacyWrappersMatchExecute(t *testing.T) {
+	srv := echoServer(t)
+	const command = `curl -s -X POST -H "X-Tenant: {{TENANT}}" {{BASE_URL}}/users/{{USER}}`
+	opts := ExecuteOptions{Variables: map[string]string{"TENANT": "acme", "USER": "42"}}
+	const want = `{"method":"POST","path":"/users/42","tenant":"acme"}`
+
+	type run struct {
+		name string
+		exec func() (*ExecutionResult, error)
+	}
+	runs := []run{
+		{"ExecuteNative", func() (*ExecutionResult, error) {
+			o := opts
+			o.Command, o.Version, o.BaseURL, o.Timeout = command, "v1", srv.URL, 5*time.Second
+			return ExecuteNative(o)
+		}},
+		{"ExecuteNativeWithOptions", func() (*ExecutionResult, error) {
+			return ExecuteNativeWithOptions(command, "v1", srv.URL, 5*time.Second, opts)
+		}},
+	}
+	if hasCurl() {
+		runs = append(runs,
+			run{"Execute", func() (*ExecutionResult, error) {
+				o := opts
+				o.Command, o.Version, o.BaseURL, o.Timeout = command, "v1", srv.URL, 5*time.Second
+				return Execute(o)
+			}},
+			run{"ExecuteWithOptions", func() (*ExecutionResult, error) {
+				return ExecuteWithOptions(command, "v1", srv.URL, 5*time.Second, opts)
+			}},
+		)
+	}
+
+	for _, r := range runs {
+		t.Run(r.name, func(t *testing.T) {
+			res, err := r.exec()
+			if err != nil {
+				t.Fatalf("%s: %v", r.name, err)
+			}
+			if string(res.Response) != want || res.Version != "v1" || res.StatusCode != http.StatusCreated {
+				t.Errorf("response = %s, version = %q, status = %d", res.Response, res.Version, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestExecuteCommandWithoutOptions(t *testing.T) {
+	requireCurl(t)
+	srv := echoServer(t)
+
+	res, err := ExecuteCommand("curl -s {{BASE_URL}}/health", "v2", srv.URL, 0)
+	if err != nil {
+		t.Fatalf("ExecuteCommand: %v", err)
+	}
+	if want := `{"method":"GET","path":"/health","tenant":""}`; string(res.Response) != want || res.Version != "v2" {
+		t.Errorf("response = %s, version = %q", res.Response, res.Version)
+	}
+}
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BaseURLPlaceholder is replaced with the version's base URL in commands
+const BaseURLPlaceholder = "{{BASE_URL}}"
+
+// placeholderPattern matches {{NAME}} tokens in commands
+var placeholderPattern = regexp.MustCompile(`\{\{([A-Za-z_][A-Za-z0-9_]*)\}\}`)
+
+// Placeholders returns the distinct user-defined placeholder names referenced
+// in a command, sorted. The built-in {{BASE_URL}} and {{BODY}} are excluded.
+func Placeholders(command string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range placeholderPattern.FindAllStringSubmatch(command, -1) {
+		name := m[1]
+		if seen[name] || "{{"+name+"}}" == BaseURLPlaceholder || "{{"+name+"}}" == BodyPlaceholder {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// substituteVariables replaces every {{NAME}} token that has a value in vars
+func substituteVariables(command string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return command
+	}
+	return placeholderPattern.ReplaceAllStringFunc(command, func(token string) string {
+		if value, ok := vars[token[2:len(token)-2]]; ok {
+			return value
+		}
+		return token
+	})
+}
+
+// SuggestPlaceholder returns the built-in or variable placeholder closest to
+// an unknown name (e.g. BASE_URL for BASE_URI), for "did you mean" hints.
+// It returns "" when nothing is within two edits.
+func SuggestPlaceholder(name string, vars map[string]string) string {
+	known := []string{BaseURLPlaceholder[2 : len(BaseURLPlaceholder)-2], BodyPlaceholder[2 : len(BodyPlaceholder)-2]}
+	for v := range vars {
+		known = append(known, v)
+	}
+	sort.Strings(known)
+
+	best, bestDist := "", 3
+	for _, candidate := range known {
+		if d := editDistance(strings.ToUpper(name), strings.ToUpper(candidate)); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// checkUnresolved returns an error naming any placeholders left in a resolved
+// command, suggesting a close match from vars or the built-ins. {{BODY}} is
+// allowed when a body will be injected.
+func checkUnresolved(command string, hasBody bool, vars map[string]string) error {
+	var missing []string
+	for _, name := range Placeholders(command) {
+		token := "{{" + name + "}}"
+		if suggestion := SuggestPlaceholder(name, vars); suggestion != "" {
+			token += " (did you mean {{" + suggestion + "}}?)"
+		}
+		missing = append(missing, token)
+	}
+	if !hasBody && strings.Contains(command, BodyPlaceholder) {
+		missing = append(missing, BodyPlaceholder)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("unresolved placeholder(s): %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveArgsNamesMistypedPlaceholder(t *testing.T) {
+	_, _, err := ResolveArgs("curl -s {{BASE_URI}}/users", "http://localhost", ExecuteOptions{})
+	if err == nil {
+		t.Fatal("ResolveArgs accepted an unresolved placeholder")
+	}
+	if want := "{{BASE_URI}} (did you mean {{BASE_URL}}?)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestSuggestPlaceholder(t *testing.T) {
+	vars := map[string]string{"TENANT_ID": "acme"}
+	tests := []struct {
+		name, want string
+	}{
+		{"BASE_URI", "BASE_URL"},
+		{"base_url", "BASE_URL"},
+		{"TENANTID", "TENANT_ID"},
+		{"BDY", "BODY"},
+		{"UNRELATED", ""},
+	}
+	for _, tt := range tests {
+		if got := SuggestPlaceholder(tt.name, vars); got != tt.want {
+			t.Errorf("SuggestPlaceholder(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package executor
+
+// applyProxyArgs adds -x <proxy> to a curl command that doesn't already set
+// a proxy. Non-curl commands and an empty proxy leave args unchanged.
+func applyProxyArgs(args []string, proxy string) []string {
+	if proxy == "" || !isCurl(args) ||
+		hasCurlFlag(args, 'x', "--proxy", "--socks5", "--socks5-hostname") {
+		return args
+	}
+	return append(args, "-x", proxy)
+}
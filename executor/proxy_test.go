@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubProxy answers every request itself, echoing the absolute URL a
+// forward proxy receives
+func stubProxy(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"proxied":%q}`, r.URL.String())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestExecuteThroughProxy(t *testing.T) {
+	proxy := stubProxy(t)
+	const want = `{"proxied":"http://upstream.invalid/users"}`
+
+	for _, r := range engines(t) {
+		t.Run(r.name, func(t *testing.T) {
+			res, err := r.exec(ExecuteOptions{
+				Command: "curl -s {{BASE_URL}}/users",
+				Version: "v1",
+				BaseURL: "http://upstream.invalid",
+				Timeout: 5 * time.Second,
+				Proxy:   proxy.URL,
+			})
+			if err != nil {
+				t.Fatalf("execute: %v (%s)", err, res.Stderr)
+			}
+			if string(res.Response) != want {
+				t.Errorf("response = %s, want %s", res.Response, want)
+			}
+		})
+	}
+}
+
+func TestApplyProxyArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"curl", "http://a"}, "curl http://a -x http://proxy:3128"},
+		{[]string{"curl", "-x", "http://other", "http://a"}, "curl -x http://other http://a"},
+		{[]string{"curl", "--proxy", "http://other", "http://a"}, "curl --proxy http://other http://a"},
+		{[]string{"grpcurl", "a:443", "svc/Method"}, "grpcurl a:443 svc/Method"},
+	}
+	for _, tt := range tests {
+		got := applyProxyArgs(slices.Clone(tt.args), "http://proxy:3128")
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("applyProxyArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+	if got := applyProxyArgs([]string{"curl", "http://a"}, ""); len(got) != 2 {
+		t.Errorf("empty proxy added args: %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// hasCurlFlag reports whether args already set a curl option, given its
+// short letter (0 if none) and long names
+func hasCurlFlag(args []string, short rune, long ...string) bool {
+	for _, arg := range args[1:] {
+		for _, name := range long {
+			if arg == name || strings.HasPrefix(arg, name+"=") {
+				return true
+			}
+		}
+		if short != 0 && (arg == "-"+string(short) || isShortFlagCluster(arg) && strings.ContainsRune(arg[1:], short)) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRedirectArgs adds -L and --max-redirs to a curl command for the
+// configured redirect policy, unless the command already sets them.
+// Non-curl commands are left unchanged.
+func applyRedirectArgs(args []string, opts ExecuteOptions) []string {
+	if !isCurl(args) {
+		return args
+	}
+	if opts.FollowRedirects && !hasCurlFlag(args, 'L', "--location", "--location-trusted") {
+		args = append(args, "-L")
+	}
+	if opts.MaxRedirects > 0 && !hasCurlFlag(args, 0, "--max-redirs") {
+		args = append(args, "--max-redirs", strconv.Itoa(opts.MaxRedirects))
+	}
+	return args
+}
+
+// redirectPolicy returns the http.Client CheckRedirect function for native
+// execution. Without follow the first response is returned as is; with a
+// positive max, following more redirects fails like curl --max-redirs.
+func redirectPolicy(follow bool, max int) func(*http.Request, []*http.Request) error {
+	if !follow {
+		return func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	if max <= 0 {
+		return nil // net/http default: up to 10 redirects
+	}
+	return func(_ *http.Request, via []*http.Request) error {
+		if len(via) > max {
+			return fmt.Errorf("maximum (%d) redirects followed", max)
+		}
+		return nil
+	}
+}
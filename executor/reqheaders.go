@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+)
+
+// injectHeaders appends -H flags for configured request headers, skipping
+// any the command already sets so commands can override shared defaults.
+// {{NAME}} placeholders in header values are resolved from vars; one left
+// unresolved is an error, as in commands. grpcurl takes the same -H flags
+// (as metadata); other commands are unchanged.
+func injectHeaders(args []string, headers map[string]string, vars map[string]string) ([]string, error) {
+	if len(headers) == 0 || !(isCurl(args) || isGrpcurl(args)) {
+		return args, nil
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if hasHeader(args, name) {
+			continue
+		}
+		value := substituteVariables(headers[name], vars)
+		if err := checkUnresolved(value, false, vars); err != nil {
+			return nil, fmt.Errorf("header %s: %w", name, err)
+		}
+		if isGrpcurl(args) {
+			args = insertFlags(args, "-H", name+": "+value)
+		} else {
+			args = append(args, "-H", name+": "+value)
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,354 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// RequestSpec is a normalized, structured view of the HTTP request described
+// by a curl command. It is used to compare what each version was asked to do.
+type RequestSpec struct {
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Headers map[string]string   `json:"headers,omitempty"`
+	Body    interface{}         `json:"body,omitempty"` // Decoded JSON when possible, raw string otherwise
+
+	// Unsupported lists flags that were recognized as curl options but are
+	// not represented in the spec (e.g. -o, --cert). Not serialized.
+	Unsupported []string `json:"-"`
+
+	// Wire-level details needed to actually send the request natively
+	target       string // Full URL including the query string
+	rawBody      string // Body exactly as curl would send it
+	basicAuth    string // user:password from -u
+	bodyFromFile bool   // A data flag read stdin (@-) or used --data-urlencode with @file
+}
+
+// redactedBasicAuth stands in the spec's headers for credentials given with
+// -u, which are sent encoded by the executor and never shown
+const redactedBasicAuth = "Basic <redacted>"
+
+// curlFlagsWithArg are curl options that consume the following argument but
+// have no effect on the request representation.
+var curlFlagsWithArg = map[string]bool{
+	"-o":                true,
+	"--output":          true,
+	"-m":                true,
+	"--max-time":        true,
+	"--connect-timeout": true,
+	"-w":                true,
+	"--write-out":       true,
+	"-x":                true,
+	"--proxy":           true,
+	"--max-redirs":      true,
+	"-D":                true,
+	"--dump-header":     true,
+	"-c":                true,
+	"--cookie-jar":      true,
+	"-r":                true,
+	"--range":           true,
+	"--cacert":          true,
+	"--cert":            true,
+	"--key":             true,
+	"--retry":           true,
+	"--retry-delay":     true,
+	"--retry-max-time":  true,
+	"-T":                true,
+	"--upload-file":     true,
+	"-F":                true,
+	"--form":            true,
+	"--resolve":         true,
+	"--connect-to":      true,
+	"-K":                true,
+	"--config":          true,
+}
+
+// curlFlagsNoArg are curl options that take no argument and do not change
+// the request representation.
+var curlFlagsNoArg = map[string]bool{
+	"-s":           true,
+	"--silent":     true,
+	"-S":           true,
+	"--show-error": true,
+	"-k":           true,
+	"--insecure":   true,
+	"-L":           true,
+	"--location":   true,
+	"-i":           true,
+	"--include":    true,
+	"-v":           true,
+	"--verbose":    true,
+	"-f":           true,
+	"--fail":       true,
+	"-g":           true,
+	"--globoff":    true,
+	"--compressed": true,
+	"--http1.1":    true,
+	"--http2":      true,
+	"-N":           true,
+	"--no-buffer":  true,
+}
+
+// dataFlags are curl options that carry a request body.
+var dataFlags = map[string]bool{
+	"-d":               true,
+	"--data":           true,
+	"--data-raw":       true,
+	"--data-binary":    true,
+	"--data-ascii":     true,
+	"--data-urlencode": true,
+	"--json":           true,
+}
+
+// ResolveCommand normalizes a command template and substitutes {{BASE_URL}},
+// producing the command string that would actually be executed.
+func ResolveCommand(commandTmpl string, baseURL string) string {
+	return ResolveCommandWithVars(commandTmpl, baseURL, nil)
+}
+
+// ResolveCommandWithVars is ResolveCommand with named {{NAME}} placeholders
+// substituted from vars first. Placeholders without a value are left as is.
+// In grpcurl commands {{BASE_URL}} becomes the address (host:port) of the
+// base URL, since grpcurl doesn't take URLs.
+func ResolveCommandWithVars(commandTmpl string, baseURL string, vars map[string]string) string {
+	cmd := substituteVariables(normalizeCommand(commandTmpl), vars)
+	if name, _, _ := strings.Cut(cmd, " "); isGrpcurl([]string{name}) {
+		baseURL = grpcAddress(baseURL)
+	}
+	return strings.ReplaceAll(cmd, BaseURLPlaceholder, baseURL)
+}
+
+// ParseRequest parses a resolved curl command into a RequestSpec.
+func ParseRequest(command string) (*RequestSpec, error) {
+	args, err := shellwords.Parse(command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse command: %w", err)
+	}
+	return ParseRequestArgs(args)
+}
+
+// ParseRequestArgs parses already-split curl arguments (including the binary
+// name) into a RequestSpec.
+func ParseRequestArgs(args []string) (*RequestSpec, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return parseCurlArgs(args[1:])
+}
+
+// parseCurlArgs builds a RequestSpec from curl arguments (without the binary name)
+func parseCurlArgs(args []string) (*RequestSpec, error) {
+	spec := &RequestSpec{Headers: make(map[string]string)}
+	var rawURL, method string
+	var bodyParts []string
+	useGet := false
+	head := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// Support the --flag=value form for long options
+		name, inlineVal, hasInline := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			if idx := strings.Index(arg, "="); idx > 0 {
+				name, inlineVal, hasInline = arg[:idx], arg[idx+1:], true
+			}
+		}
+
+		// value returns the option argument, consuming the next arg if needed
+		value := func() (string, error) {
+			if hasInline {
+				return inlineVal, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("option %s requires an argument", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch {
+		case name == "-X" || name == "--request":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			method = strings.ToUpper(v)
+		case name == "-H" || name == "--header":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if k, hv, ok := strings.Cut(v, ":"); ok {
+				spec.Headers[http.CanonicalHeaderKey(strings.TrimSpace(k))] = strings.TrimSpace(hv)
+			}
+		case dataFlags[name]:
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(v, "@") && name != "--data-raw" {
+				if fileDataFlags[name] && v != "@-" {
+					if v, err = readDataFile(name, v[1:]); err != nil {
+						return nil, err
+					}
+				} else {
+					spec.bodyFromFile = true
+				}
+			}
+			if name == "--json" {
+				spec.Headers["Content-Type"] = "application/json"
+			}
+			bodyParts = append(bodyParts, v)
+		case name == "-A" || name == "--user-agent":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			spec.Headers["User-Agent"] = v
+		case name == "-e" || name == "--referer":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			spec.Headers["Referer"] = v
+		case name == "-b" || name == "--cookie":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			spec.Headers["Cookie"] = v
+		case name == "-u" || name == "--user":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			spec.Headers["Authorization"] = redactedBasicAuth
+			spec.basicAuth = v
+		case name == "--url":
+			v, err := value()
+			if err != nil {
+				return nil, err
+			}
+			rawURL = v
+		case name == "-G" || name == "--get":
+			useGet = true
+		case name == "-I" || name == "--head":
+			head = true
+		case curlFlagsWithArg[name]:
+			if _, err := value(); err != nil {
+				return nil, err
+			}
+			spec.Unsupported = append(spec.Unsupported, name)
+		case curlFlagsNoArg[name]:
+			spec.Unsupported = append(spec.Unsupported, name)
+		case isShortFlagCluster(arg):
+			// e.g. -sSL: every letter must be a known no-arg flag
+			for _, c := range arg[1:] {
+				flag := "-" + string(c)
+				switch {
+				case flag == "-G":
+					useGet = true
+				case flag == "-I":
+					head = true
+				case curlFlagsNoArg[flag]:
+					spec.Unsupported = append(spec.Unsupported, flag)
+				default:
+					return nil, fmt.Errorf("unsupported option %s in %s", flag, arg)
+				}
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unsupported option %s", arg)
+		default:
+			if rawURL != "" {
+				return nil, fmt.Errorf("multiple URLs are not supported: %s", arg)
+			}
+			rawURL = arg
+		}
+	}
+
+	if rawURL == "" {
+		return nil, fmt.Errorf("no URL found in command")
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	query := parsed.Query()
+
+	body := strings.Join(bodyParts, "&")
+	if useGet && body != "" {
+		// -G moves the data into the query string
+		if extra, err := url.ParseQuery(body); err == nil {
+			for k, vs := range extra {
+				query[k] = append(query[k], vs...)
+			}
+		}
+		body = ""
+	}
+
+	switch {
+	case method != "":
+		spec.Method = method
+	case head:
+		spec.Method = http.MethodHead
+	case body != "":
+		spec.Method = http.MethodPost
+	default:
+		spec.Method = http.MethodGet
+	}
+
+	spec.rawBody = body
+	spec.target = rawURL
+	if useGet && len(query) > 0 {
+		target := *parsed
+		target.RawQuery = query.Encode()
+		spec.target = target.String()
+	}
+
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	spec.URL = parsed.String()
+	if len(query) > 0 {
+		spec.Query = make(map[string][]string, len(query))
+		for k, vs := range query {
+			sorted := append([]string(nil), vs...)
+			sort.Strings(sorted)
+			spec.Query[k] = sorted
+		}
+	}
+	if len(spec.Headers) == 0 {
+		spec.Headers = nil
+	}
+	if body != "" {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(body), &decoded); err == nil {
+			spec.Body = decoded
+		} else {
+			spec.Body = body
+		}
+	}
+
+	return spec, nil
+}
+
+// isShortFlagCluster reports whether arg looks like combined short flags (-sSL)
+func isShortFlagCluster(arg string) bool {
+	if len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return false
+	}
+	for _, c := range arg[1:] {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -3,7 +3,11 @@ package executor
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -24,6 +28,38 @@ type ExecutionResult struct {
 	Error     string    `json:"error,omitempty"`
 	Stderr    string    `json:"stderr,omitempty"`    // Always capture stderr for debugging
 	TimedOut  bool      `json:"timed_out,omitempty"` // True if command exceeded timeout
+
+	// StatusCode is the HTTP status of the response, or 0 if it could not be captured
+	StatusCode int `json:"status_code,omitempty"`
+
+	// ContentType is the response's Content-Type header, if it could be captured
+	ContentType string `json:"content_type,omitempty"`
+
+	// Headers holds the response headers (of the final response, when
+	// redirects were followed). Only captured with ExecuteOptions.CaptureHeaders.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Trailers holds HTTP trailers sent after the body (streaming/gRPC-web
+	// endpoints). Only populated by execution modes that can capture them.
+	Trailers map[string]string `json:"trailers,omitempty"`
+
+	// FinalURL is the URL that produced the response, after any redirects
+	// were followed (empty if it could not be captured), and Redirects the
+	// number of redirects followed to get there
+	FinalURL  string `json:"final_url,omitempty"`
+	Redirects int    `json:"redirects,omitempty"`
+
+	// Truncated is set when the body exceeded ExecuteOptions.MaxResponseBytes
+	// and only that many bytes were kept
+	Truncated bool `json:"truncated,omitempty"`
+
+	// ResponseFile is where ExecuteOptions.ResponseSink stored the body, in
+	// which case Response is nil
+	ResponseFile string `json:"response_file,omitempty"`
+
+	// Partial is set when the command timed out after part of the body
+	// arrived; Response (or ResponseFile) holds what was received
+	Partial bool `json:"partial,omitempty"`
 }
 
 // normalizeCommand removes backslash line continuations, tabs, and extra whitespace
@@ -46,50 +82,176 @@ func normalizeCommand(cmd string) string {
 	return strings.TrimSpace(cmd)
 }
 
-// validateCommand checks if the command appears to be a curl command
-// Returns a warning message if not curl, empty string if valid
+// MissingToolError reports that the binary a command invokes is not installed
+type MissingToolError struct {
+	Tool string
+}
+
+func (e *MissingToolError) Error() string {
+	msg := fmt.Sprintf("'%s' was not found on PATH - install %s or add it to PATH to run these commands", e.Tool, e.Tool)
+	if strings.EqualFold(strings.TrimSuffix(e.Tool, ".exe"), "curl") {
+		msg += `, or set "engine": "native" to run them without curl`
+	}
+	return msg
+}
+
+// RequireTool verifies that the named binary is available on PATH
+func RequireTool(name string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return &MissingToolError{Tool: name}
+	}
+	return nil
+}
+
+// CheckCommandTool returns the binary a command template invokes and verifies
+// it is available on PATH. A *MissingToolError is returned if it is not.
+func CheckCommandTool(commandTmpl string) (string, error) {
+	args, err := shellwords.Parse(normalizeCommand(commandTmpl))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse command: %w", err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("empty command")
+	}
+	return args[0], RequireTool(args[0])
+}
+
+// knownTools are the binaries commands are expected to invoke. Other
+// binaries still run, with a warning.
+var knownTools = map[string]bool{"curl": true, "grpcurl": true}
+
+// validateCommand checks if the command invokes a known tool (curl or
+// grpcurl). Returns a warning message if not, empty string if valid.
 func validateCommand(args []string) string {
 	if len(args) == 0 {
 		return "empty command"
 	}
-	cmdName := strings.ToLower(args[0])
-	if cmdName != "curl" && cmdName != "curl.exe" {
-		return fmt.Sprintf("command '%s' is not curl - execution may behave unexpectedly", args[0])
+	if !knownTools[toolName(args)] {
+		return fmt.Sprintf("command '%s' is not curl or grpcurl - execution may behave unexpectedly", args[0])
 	}
 	return ""
 }
 
-// Execute runs the curl command after replacing {{BASE_URL}} with the target base URL.
-// Uses the provided timeout, or DefaultTimeout if timeout is 0.
-func Execute(commandTmpl string, version string, baseURL string, timeout time.Duration) (*ExecutionResult, error) {
-	if timeout <= 0 {
-		timeout = DefaultTimeout
+// toolName returns the lowercased binary a command invokes, without ".exe"
+func toolName(args []string) string {
+	if len(args) == 0 {
+		return ""
 	}
+	return strings.TrimSuffix(strings.ToLower(args[0]), ".exe")
+}
 
-	// 1. Normalize command (handle line continuations, tabs, etc.)
-	normalizedCmd := normalizeCommand(commandTmpl)
+// isCurl reports whether a command invokes curl. Only curl commands get the
+// flags the executor adds for status codes, headers, redirects, proxies and TLS.
+func isCurl(args []string) bool {
+	return toolName(args) == "curl"
+}
 
-	// 2. Replace placeholder
-	finalCmdStr := strings.ReplaceAll(normalizedCmd, "{{BASE_URL}}", baseURL)
+// ExecuteOptions carries everything needed to execute a command. Only
+// Command is required; new behavior is added here as fields, so callers that
+// don't set them are unaffected.
+type ExecuteOptions struct {
+	// Command is the command template, with {{BASE_URL}} and other placeholders
+	Command string
 
-	// 3. Parse command into args
-	args, err := shellwords.Parse(finalCmdStr)
-	if err != nil {
-		return &ExecutionResult{
-			Command:   finalCmdStr,
-			Version:   version,
-			Timestamp: time.Now(),
-			Error:     fmt.Sprintf("failed to parse command: %v", err),
-		}, fmt.Errorf("failed to parse command: %w", err)
+	// Version names the version being executed (used in results and warnings)
+	Version string
+
+	// BaseURL replaces {{BASE_URL}} in Command
+	BaseURL string
+
+	// Timeout bounds the execution (0 = DefaultTimeout)
+	Timeout time.Duration
+
+	// Context cancels the execution early when it is done, killing the
+	// command or aborting the request (nil = never cancelled)
+	Context context.Context
+
+	// InjectDelay and InjectJitter add an artificial pause (delay plus a random
+	// amount up to jitter) before the command runs. Test-only: used to
+	// reproduce timing-dependent behavior in the engine.
+	InjectDelay  time.Duration
+	InjectJitter time.Duration
+
+	// Body is a JSON request body shared across versions. BodyRenames maps
+	// field paths in Body to new field names for this version. The body
+	// replaces {{BODY}} in the command, or is appended as --data-raw.
+	Body        []byte
+	BodyRenames map[string]string
+
+	// Variables holds values for named {{NAME}} placeholders in the command
+	Variables map[string]string
+
+	// BaseDir is the directory "@file" data arguments (-d @body.json) are
+	// resolved against, normally the config file's directory. Empty uses the
+	// working directory.
+	BaseDir string
+
+	// Headers are request headers added to the command as -H flags, unless
+	// it already sets them. Values may use {{NAME}} placeholders.
+	Headers map[string]string
+
+	// FollowRedirects follows HTTP redirects (adds -L to curl commands that
+	// don't have it). MaxRedirects caps how many are followed (0 = default).
+	FollowRedirects bool
+	MaxRedirects    int
+
+	// Proxy sends the request through this proxy URL (curl commands get -x
+	// unless they set a proxy). Empty leaves the proxy environment variables
+	// in charge.
+	Proxy string
+
+	// InsecureSkipVerify disables TLS certificate verification (curl
+	// commands get -k unless they already have it)
+	InsecureSkipVerify bool
+
+	// MaxResponseBytes caps how much of the response body is kept in memory;
+	// the rest is discarded and ExecutionResult.Truncated set
+	// (0 = DefaultMaxResponseBytes)
+	MaxResponseBytes int64
+
+	// ResponseSink, when set, receives the response body instead of it being
+	// read into ExecutionResult.Response, so large bodies can go straight to
+	// disk. It is called once StatusCode and Headers are set, must apply
+	// MaxResponseBytes (setting Truncated) itself, and returns where the body
+	// was stored. Only ExecuteNative supports it; Execute ignores it.
+	ResponseSink func(result *ExecutionResult, body io.Reader) (string, error)
+
+	// CaptureHeaders records the response headers in ExecutionResult.Headers
+	// (curl commands get -D unless they already dump or include headers)
+	CaptureHeaders bool
+}
+
+// Execute runs a command with the default CurlExecutor
+func Execute(opts ExecuteOptions) (*ExecutionResult, error) {
+	return CurlExecutor{}.Execute(opts)
+}
+
+// Execute runs opts.Command after replacing {{BASE_URL}} with opts.BaseURL
+// and any named placeholders with opts.Variables. It uses opts.Timeout, or
+// DefaultTimeout if that is 0.
+func (CurlExecutor) Execute(opts ExecuteOptions) (*ExecutionResult, error) {
+	commandTmpl, version, baseURL, timeout := opts.Command, opts.Version, opts.BaseURL, opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
 	}
 
-	if len(args) == 0 {
+	if delay := injectedDelay(opts); delay > 0 {
+		time.Sleep(delay)
+	}
+
+	// 1-3. Resolve the command into its final argument list
+	finalCmdStr, args, err := ResolveArgs(commandTmpl, baseURL, opts)
+	if err != nil {
+		errMsg := err.Error()
+		if errors.Is(err, errEmptyCommand) {
+			errMsg = "empty command after parsing"
+		}
 		return &ExecutionResult{
 			Command:   finalCmdStr,
 			Version:   version,
 			Timestamp: time.Now(),
-			Error:     "empty command after parsing",
-		}, fmt.Errorf("empty command")
+			Error:     errMsg,
+		}, err
 	}
 
 	// 4. Validate command (warn if not curl)
@@ -98,17 +260,37 @@ func Execute(commandTmpl string, version string, baseURL string, timeout time.Du
 		fmt.Printf("[WARN] %s: %s\n", version, warning)
 	}
 
+	args = applyRedirectArgs(args, opts)
+	args = applyProxyArgs(args, opts.Proxy)
+	args = applyInsecureArgs(args, opts.InsecureSkipVerify)
+
+	var headerFile string
+	if opts.CaptureHeaders {
+		args, headerFile, err = injectHeaderDump(args)
+		if err != nil {
+			fmt.Printf("[WARN] %s: cannot capture headers: %v\n", version, err)
+		}
+		if headerFile != "" {
+			defer os.Remove(headerFile)
+		}
+	}
+
+	// Capture the HTTP status code alongside the body
+	args, statusInjected := injectStatusFormat(args)
+	args = unbufferOutput(args)
+
 	cmdName := args[0]
 	cmdArgs := args[1:]
 
 	// 5. Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(opts.parentContext(), timeout)
 	defer cancel()
 
 	start := time.Now()
 	cmd := exec.CommandContext(ctx, cmdName, cmdArgs...)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	stdout := newCappedBuffer(opts.MaxResponseBytes)
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
 	cmd.Stderr = &stderr
 
 	err = cmd.Run()
@@ -123,9 +305,18 @@ func Execute(commandTmpl string, version string, baseURL string, timeout time.Du
 	}
 
 	// Check if the error was due to context timeout
+	head, tail := stdout.output()
 	if ctx.Err() == context.DeadlineExceeded {
+		// curl was killed before writing its status, so whatever it printed
+		// is body; keep it for diagnosis
 		result.TimedOut = true
 		result.Error = fmt.Sprintf("command timed out after %s", timeout)
+		result.Response, result.Truncated = head, tail != nil
+		result.Partial = len(head) > 0
+		return result, ctx.Err()
+	}
+	if ctx.Err() == context.Canceled {
+		result.Error = errCancelled
 		return result, ctx.Err()
 	}
 
@@ -137,11 +328,115 @@ func Execute(commandTmpl string, version string, baseURL string, timeout time.Du
 		return result, err
 	}
 
-	result.Response = stdout.Bytes()
+	if statusInjected {
+		var status writeOutStatus
+		result.Response, result.Truncated, status = extractCappedStatus(head, tail)
+		status.apply(result)
+	} else {
+		result.Response, result.Truncated = head, tail != nil
+	}
+	if headerFile != "" {
+		result.Headers = readHeaderDump(headerFile)
+	}
 	return result, nil
 }
 
+// errEmptyCommand is returned when a command has no arguments after parsing
+var errEmptyCommand = errors.New("empty command")
+
+// ResolveArgs normalizes the command template, replaces placeholders, parses
+// it into arguments and applies option-driven arguments (such as the request
+// body). It returns the resolved command string alongside the arguments.
+func ResolveArgs(commandTmpl string, baseURL string, opts ExecuteOptions) (string, []string, error) {
+	// 1. Normalize command (handle line continuations, tabs, etc.)
+	// 2. Replace placeholders, refusing to send unresolved ones literally
+	finalCmdStr := ResolveCommandWithVars(commandTmpl, baseURL, opts.Variables)
+	if err := checkUnresolved(finalCmdStr, len(opts.Body) > 0, opts.Variables); err != nil {
+		return finalCmdStr, nil, err
+	}
+
+	// 3. Parse command into args
+	args, err := shellwords.Parse(finalCmdStr)
+	if err != nil {
+		return finalCmdStr, nil, fmt.Errorf("failed to parse command: %w", err)
+	}
+	if len(args) == 0 {
+		return finalCmdStr, nil, errEmptyCommand
+	}
+
+	if args, err = resolveDataFiles(args, opts.BaseDir); err != nil {
+		return finalCmdStr, nil, err
+	}
+
+	// Configured headers go first, so a Content-Type among them is kept
+	// when a body is injected
+	if args, err = injectHeaders(args, opts.Headers, opts.Variables); err != nil {
+		return finalCmdStr, nil, err
+	}
+
+	if len(opts.Body) > 0 {
+		body, err := BuildBody(opts.Body, opts.BodyRenames)
+		if err != nil {
+			return finalCmdStr, nil, err
+		}
+		args = injectBody(args, body)
+	}
+
+	return finalCmdStr, args, nil
+}
+
+// PlanCommand returns the arguments Execute would run for opts without
+// running them: placeholders, headers and body are resolved and the redirect,
+// proxy and TLS options applied. Arguments Execute only adds to capture the
+// result (status format, header dump) are left out.
+func PlanCommand(opts ExecuteOptions) ([]string, error) {
+	_, args, err := ResolveArgs(opts.Command, opts.BaseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	args = applyRedirectArgs(args, opts)
+	args = applyProxyArgs(args, opts.Proxy)
+	args = applyInsecureArgs(args, opts.InsecureSkipVerify)
+	return args, nil
+}
+
+// errCancelled is the result error of an execution stopped by its Context
+const errCancelled = "execution cancelled"
+
+// parentContext returns the context executions derive their timeout from
+func (opts ExecuteOptions) parentContext() context.Context {
+	if opts.Context == nil {
+		return context.Background()
+	}
+	return opts.Context
+}
+
+// injectedDelay returns the test-only delay to apply before executing
+func injectedDelay(opts ExecuteOptions) time.Duration {
+	delay := opts.InjectDelay
+	if opts.InjectJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(opts.InjectJitter)))
+	}
+	return delay
+}
+
+// ExecuteCommand runs a command with the given timeout and no other options.
+//
+// Deprecated: use Execute with an ExecuteOptions.
+func ExecuteCommand(commandTmpl string, version string, baseURL string, timeout time.Duration) (*ExecutionResult, error) {
+	return Execute(ExecuteOptions{Command: commandTmpl, Version: version, BaseURL: baseURL, Timeout: timeout})
+}
+
+// ExecuteWithOptions runs Execute with the command inputs given separately
+// from the other options.
+//
+// Deprecated: set Command, Version, BaseURL and Timeout in opts and call Execute.
+func ExecuteWithOptions(commandTmpl string, version string, baseURL string, timeout time.Duration, opts ExecuteOptions) (*ExecutionResult, error) {
+	opts.Command, opts.Version, opts.BaseURL, opts.Timeout = commandTmpl, version, baseURL, timeout
+	return Execute(opts)
+}
+
 // ExecuteWithDefaults runs Execute with default timeout
 func ExecuteWithDefaults(commandTmpl string, version string, baseURL string) (*ExecutionResult, error) {
-	return Execute(commandTmpl, version, baseURL, DefaultTimeout)
+	return Execute(ExecuteOptions{Command: commandTmpl, Version: version, BaseURL: baseURL})
 }
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// slowServer answers after delay, or when the client goes away
+func slowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			w.Write([]byte(`{"ok":true}`))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// engine is an executor entry point that tests run the same options through
+type engine struct {
+	name string
+	exec func(ExecuteOptions) (*ExecutionResult, error)
+}
+
+// engines returns the native engine, plus the curl one when curl is installed
+func engines(t *testing.T) []engine {
+	t.Helper()
+	runs := []engine{{"native", ExecuteNative}}
+	if hasCurl() {
+		runs = append(runs, engine{"curl", Execute})
+	} else {
+		t.Log("curl not installed, testing the native engine only")
+	}
+	return runs
+}
+
+func hasCurl() bool {
+	_, err := exec.LookPath("curl")
+	return err == nil
+}
+
+func requireCurl(t *testing.T) {
+	t.Helper()
+	if !hasCurl() {
+		t.Skip("curl not installed")
+	}
+}
+
+func TestExecuteStopsWhenContextCancelled(t *testing.T) {
+	requireCurl(t)
+	srv := slowServer(t, 10*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	res, err := Execute(ExecuteOptions{
+		Command: "curl -s {{BASE_URL}}/slow",
+		Version: "v1",
+		BaseURL: srv.URL,
+		Timeout: 10 * time.Second,
+		Context: ctx,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled execution took %s", elapsed)
+	}
+	if res.Error != errCancelled || res.TimedOut {
+		t.Errorf("result error = %q, timed out = %v", res.Error, res.TimedOut)
+	}
+}
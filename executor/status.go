@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// statusMarker separates the response body from the status code that curl
+// writes after it via --write-out
+const statusMarker = "\n__api_diff_checker_status__:"
+
+// injectStatusFormat asks curl to append the HTTP status code, redirect count,
+// effective URL and content type to its output. Non-curl commands and commands that set their own
+// --write-out are left unchanged; the boolean reports whether the format was added.
+func injectStatusFormat(args []string) ([]string, bool) {
+	if !isCurl(args) {
+		return args, false
+	}
+	for _, arg := range args[1:] {
+		if arg == "-w" || strings.HasPrefix(arg, "--write-out") {
+			return args, false
+		}
+		if isShortFlagCluster(arg) && strings.ContainsRune(arg[1:], 'w') {
+			return args, false
+		}
+	}
+
+	out := make([]string, len(args), len(args)+2)
+	copy(out, args)
+	return append(out, "-w", statusMarker+"%{http_code}\t%{num_redirects}\t%{url_effective}\t%{content_type}"), true
+}
+
+// unbufferOutput makes curl write the body as it arrives instead of
+// buffering stdout, so a command killed by the timeout leaves its partial
+// output behind. Non-curl commands are left unchanged.
+func unbufferOutput(args []string) []string {
+	if !isCurl(args) {
+		return args
+	}
+	for _, arg := range args[1:] {
+		if arg == "-N" || arg == "--no-buffer" {
+			return args
+		}
+	}
+	out := make([]string, len(args), len(args)+1)
+	copy(out, args)
+	return append(out, "--no-buffer")
+}
+
+// writeOutStatus is what curl reports after the body via the status format
+type writeOutStatus struct {
+	code        int
+	redirects   int
+	finalURL    string
+	contentType string
+}
+
+// apply copies the reported status into result
+func (s writeOutStatus) apply(result *ExecutionResult) {
+	result.StatusCode = s.code
+	result.Redirects = s.redirects
+	result.FinalURL = s.finalURL
+	result.ContentType = s.contentType
+}
+
+// extractStatus strips the status marker from curl output and returns the
+// body and the reported status. The code is 0 if it could not be determined,
+// in which case the output is returned unchanged.
+func extractStatus(out []byte) ([]byte, writeOutStatus) {
+	idx := bytes.LastIndex(out, []byte(statusMarker))
+	if idx < 0 {
+		return out, writeOutStatus{}
+	}
+	fields := strings.SplitN(strings.TrimSpace(string(out[idx+len(statusMarker):])), "\t", 4)
+	code, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return out, writeOutStatus{}
+	}
+	status := writeOutStatus{code: code}
+	if len(fields) >= 3 {
+		status.redirects, _ = strconv.Atoi(fields[1])
+		status.finalURL = fields[2]
+	}
+	if len(fields) == 4 {
+		status.contentType = fields[3]
+	}
+	return out[:idx], status
+}
+
+// extractCappedStatus is extractStatus for output kept by a cappedBuffer,
+// where the status marker may be in the discarded tail or straddle the
+// limit. It also reports whether the body itself was cut short.
+func extractCappedStatus(head, tail []byte) ([]byte, bool, writeOutStatus) {
+	if tail == nil {
+		body, status := extractStatus(head)
+		return body, false, status
+	}
+
+	start := len(head) - cappedTailSize
+	if start < 0 {
+		start = 0
+	}
+	joined := append(append([]byte(nil), head[start:]...), tail...)
+	rest, status := extractStatus(joined)
+	if status.code == 0 {
+		return head, true, writeOutStatus{}
+	}
+	if end := start + len(rest); end <= len(head) {
+		// Only the status overflowed; the body is complete
+		return head[:end], false, status
+	}
+	return head, true, status
+}
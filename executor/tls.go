@@ -0,0 +1,11 @@
+package executor
+
+// applyInsecureArgs adds -k to a curl command that doesn't already skip TLS
+// verification. Non-curl commands are left unchanged.
+func applyInsecureArgs(args []string, insecure bool) []string {
+	if !insecure || !isCurl(args) ||
+		hasCurlFlag(args, 'k', "--insecure") {
+		return args
+	}
+	return append(args, "-k")
+}
@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInsecureSkipVerifyReachesTransport(t *testing.T) {
+	// httptest's certificate is self-signed, so verification fails unless skipped
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	for _, r := range engines(t) {
+		t.Run(r.name, func(t *testing.T) {
+			opts := ExecuteOptions{
+				Command: "curl -s {{BASE_URL}}/",
+				Version: "v1",
+				BaseURL: srv.URL,
+				Timeout: 5 * time.Second,
+			}
+			if res, err := r.exec(opts); err == nil && string(res.Response) == `{"ok":true}` {
+				t.Fatal("self-signed certificate accepted without insecure_skip_verify")
+			}
+
+			opts.InsecureSkipVerify = true
+			res, err := r.exec(opts)
+			if err != nil {
+				t.Fatalf("execute with InsecureSkipVerify: %v (%s)", err, res.Stderr)
+			}
+			if string(res.Response) != `{"ok":true}` {
+				t.Errorf("response = %s", res.Response)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/core"
+	"api_diff_checker/storage"
+)
+
+// goldenMode controls how golden mismatches are handled
+type goldenMode int
+
+const (
+	goldenCheck     goldenMode = iota // Report mismatches and fail
+	goldenReview                      // Prompt to accept or reject each mismatch
+	goldenAcceptAll                   // Accept every mismatch
+	goldenRejectAll                   // Reject every mismatch
+)
+
+// goldenModeFromFlags resolves the golden review flags into a mode
+func goldenModeFromFlags(dir string, review, acceptAll, rejectAll bool) (goldenMode, error) {
+	set := 0
+	for _, b := range []bool{review, acceptAll, rejectAll} {
+		if b {
+			set++
+		}
+	}
+	if set > 1 {
+		return goldenCheck, errors.New("--review, --accept-all and --reject-all are mutually exclusive")
+	}
+	if set == 1 && dir == "" {
+		return goldenCheck, errors.New("--review, --accept-all and --reject-all require --golden")
+	}
+
+	switch {
+	case review:
+		return goldenReview, nil
+	case acceptAll:
+		return goldenAcceptAll, nil
+	case rejectAll:
+		return goldenRejectAll, nil
+	}
+	return goldenCheck, nil
+}
+
+// checkGoldens compares every saved response of the run against its golden
+// response and handles mismatches according to mode. It returns the number
+// of mismatches left unapproved.
+func checkGoldens(result *core.RunResult, golden *storage.GoldenStore, mode goldenMode, in io.Reader) (int, error) {
+	reader := bufio.NewReader(in)
+	unapproved := 0
+
+	for _, cmdRes := range result.CommandResults {
+		for _, info := range cmdRes.ExecInfo {
+			if info.File == "" {
+				continue
+			}
+			command := cmdRes.Commands[info.Version]
+
+			current, err := storage.ReadResponse(info.File)
+			if err != nil {
+				return unapproved, fmt.Errorf("read response %s: %w", info.File, err)
+			}
+
+			entry, exists := golden.Lookup(command, info.Version)
+			if exists && entry.ContentHash == storage.ContentHash(current) {
+				continue // Unchanged, nothing to review
+			}
+
+			fmt.Printf("\n=== Golden mismatch: %s (%s) ===\n", cmdRes.TestCaseName, info.Version)
+			if !exists {
+				fmt.Println("No golden response recorded yet.")
+			} else {
+				approved, err := golden.Read(entry)
+				if err != nil {
+					return unapproved, err
+				}
+				diff, err := comparator.Compare(approved, current, "golden", info.Version)
+				if err != nil {
+					return unapproved, err
+				}
+				fmt.Println(diff.TextDiff)
+				fmt.Printf("Summary: %s\n", diff.Summary)
+			}
+
+			accept := false
+			switch mode {
+			case goldenAcceptAll:
+				accept = true
+			case goldenReview:
+				var quit bool
+				accept, quit = promptAccept(reader)
+				if quit {
+					mode = goldenRejectAll
+				}
+			}
+
+			if !accept {
+				unapproved++
+				continue
+			}
+			if err := golden.Approve(command, info.Version, current); err != nil {
+				return unapproved, err
+			}
+			fmt.Println("Golden updated.")
+		}
+	}
+
+	return unapproved, nil
+}
+
+// promptAccept asks whether to accept the current response as golden.
+// quit is true when the user chose to reject all remaining mismatches.
+func promptAccept(reader *bufio.Reader) (accept bool, quit bool) {
+	for {
+		fmt.Print("Accept as golden? [y]es / [n]o / [q]uit (reject remaining): ")
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			// No more input: treat as reject
+			return false, true
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return true, false
+		case "n", "no", "":
+			return false, false
+		case "q", "quit":
+			return false, true
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rotatedTimeFormat is the timestamp suffix of rotated log files
+const rotatedTimeFormat = "20060102T150405"
+
+// pruneBackups deletes the oldest rotated copies of logPath
+// (logPath.<timestamp>, optionally .gz) beyond keep. Files are ordered by the
+// timestamp in their name rather than mtime; a plain file and its .gz
+// (while compression is in progress) count as one backup.
+func pruneBackups(logPath string, keep int) error {
+	matches, err := filepath.Glob(logPath + ".*")
+	if err != nil {
+		return err
+	}
+
+	byTime := make(map[time.Time][]string)
+	for _, path := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(path, logPath+"."), ".gz")
+		ts, err := time.Parse(rotatedTimeFormat, suffix)
+		if err != nil {
+			continue // Not one of our rotated files
+		}
+		byTime[ts] = append(byTime[ts], path)
+	}
+	if len(byTime) <= keep {
+		return nil
+	}
+
+	times := make([]time.Time, 0, len(byTime))
+	for ts := range byTime {
+		times = append(times, ts)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+
+	var firstErr error
+	for _, ts := range times[keep:] {
+		for _, path := range byTime[ts] {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// compressFile gzips path to path.gz and removes the original. On failure
+// the partial .gz is removed and the original is kept, so nothing is lost.
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gzPath := path + ".gz"
+	out, err := os.OpenFile(gzPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(gzPath)
+		return err
+	}
+
+	in.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompressFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "execution.log.1")
+	content := bytes.Repeat([]byte(`{"level":"INFO","message":"executed"}`+"\n"), 100)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := compressFile(path); err != nil {
+		t.Fatalf("compressFile: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("original still present after compressing: %v", err)
+	}
+
+	f, err := os.Open(path + ".gz")
+	if err != nil {
+		t.Fatalf("opening compressed file: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("reading compressed file: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("decompressed %d bytes, want the original %d", len(got), len(content))
+	}
+}
+
+func TestCompressFileKeepsOriginalOnFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "execution.log.1")
+	if err := os.WriteFile(path, []byte("entry\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// An existing archive is never overwritten
+	if err := os.WriteFile(path+".gz", []byte("older archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := compressFile(path); err == nil {
+		t.Fatal("compressFile overwrote an existing archive")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "entry\n" {
+		t.Errorf("original = %q, %v; want it kept", data, err)
+	}
+	if data, _ := os.ReadFile(path + ".gz"); string(data) != "older archive" {
+		t.Errorf("existing archive changed to %q", data)
+	}
+}
@@ -3,9 +3,13 @@ package logger
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
+
+	"api_diff_checker/clock"
 )
 
 type LogEntry struct {
@@ -17,12 +21,54 @@ type LogEntry struct {
 	ErrorDetails string    `json:"error_details,omitempty"`
 }
 
+// Log levels, from least to most severe
+const (
+	LevelDebug = "DEBUG"
+	LevelInfo  = "INFO"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+)
+
+// levelRank orders the log levels; unknown levels rank as INFO
+var levelRank = map[string]int{
+	LevelDebug: 0,
+	LevelInfo:  1,
+	LevelWarn:  2,
+	LevelError: 3,
+}
+
+// rank returns the ordering of a level
+func rank(level string) int {
+	if r, ok := levelRank[level]; ok {
+		return r
+	}
+	return levelRank[LevelInfo]
+}
+
 type Logger struct {
+	// MinLevel suppresses entries below this level in both the file and
+	// stdout. Empty means LevelInfo.
+	MinLevel string
+
+	// CompressRotated gzips rotated log files in the background
+	// (execution.log.<timestamp>.gz). Enabled by New.
+	CompressRotated bool
+	compressing     sync.WaitGroup
+
+	// MaxBackups keeps at most this many rotated log files, deleting the
+	// oldest after each rotation (0 = keep all)
+	MaxBackups int
+
+	// Clock timestamps entries that don't carry one (nil = the system
+	// clock). Rotated file names always use the system clock.
+	Clock clock.Clock
+
 	mu       sync.Mutex
-	LogFile  *os.File
+	LogFile  *os.File  // Set when the sink is a file; nil for NewWithWriter sinks
+	out      io.Writer // JSON sink, one entry per line
 	filePath string
 	toStdOut bool
-	maxSize  int64 // Maximum log file size in bytes (0 = no limit)
+	maxSize  int64 // Maximum log file size in bytes (0 = no limit; files only)
 }
 
 const (
@@ -30,18 +76,25 @@ const (
 	DefaultMaxLogSize = 10 * 1024 * 1024
 )
 
-// New creates a new logger that writes to the specified file
+// New creates a new logger that writes to the specified file, rotating it
+// once it reaches DefaultMaxLogSize
 func New(logPath string, toStdOut bool) (*Logger, error) {
 	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
-	return &Logger{
-		LogFile:  f,
-		filePath: logPath,
-		toStdOut: toStdOut,
-		maxSize:  DefaultMaxLogSize,
-	}, nil
+	l := NewWithWriter(f, toStdOut)
+	l.LogFile = f
+	l.filePath = logPath
+	l.maxSize = DefaultMaxLogSize
+	l.CompressRotated = true
+	return l, nil
+}
+
+// NewWithWriter creates a logger that writes JSON entries, one per line, to
+// w (a file, socket, buffer...). There is no rotation, and Close leaves w open.
+func NewWithWriter(w io.Writer, toStdOut bool) *Logger {
+	return &Logger{out: w, toStdOut: toStdOut}
 }
 
 // NewWithMaxSize creates a logger with a custom max size
@@ -59,12 +112,16 @@ func (l *Logger) Log(entry LogEntry) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if rank(entry.Level) < rank(l.MinLevel) {
+		return
+	}
+
 	if entry.Timestamp.IsZero() {
-		entry.Timestamp = time.Now()
+		entry.Timestamp = clock.Or(l.Clock).Now()
 	}
 
-	// Check if log rotation is needed
-	if l.maxSize > 0 {
+	// Check if log rotation is needed (file sinks only)
+	if l.maxSize > 0 && l.LogFile != nil {
 		if err := l.checkRotation(); err != nil {
 			// Log rotation error to stderr as fallback
 			fmt.Fprintf(os.Stderr, "[LOGGER ERROR] Failed to rotate log: %v\n", err)
@@ -79,17 +136,14 @@ func (l *Logger) Log(entry LogEntry) {
 		return
 	}
 
-	if _, err := l.LogFile.Write(data); err != nil {
+	// One write per entry keeps lines intact on shared sinks
+	if _, err := l.out.Write(append(data, '\n')); err != nil {
 		// Log write error to stderr as fallback
 		fmt.Fprintf(os.Stderr, "[LOGGER ERROR] Failed to write to log file: %v\n", err)
 		// Also print the original log entry to stderr so it's not lost
 		fmt.Fprintf(os.Stderr, "[FALLBACK] %s: %s\n", entry.Level, entry.Message)
 	}
 
-	if _, err := l.LogFile.WriteString("\n"); err != nil {
-		fmt.Fprintf(os.Stderr, "[LOGGER ERROR] Failed to write newline to log file: %v\n", err)
-	}
-
 	// Terminal output (human-readable)
 	if l.toStdOut {
 		l.printToStdout(entry)
@@ -106,6 +160,8 @@ func (l *Logger) printToStdout(entry LogEntry) {
 		fmt.Println()
 	} else if entry.Level == "WARN" {
 		fmt.Printf("[WARN] %v: %s\n", entry.Version, entry.Message)
+	} else if entry.Level == LevelDebug {
+		fmt.Printf("[DEBUG] %v: %s\n", entry.Version, entry.Message)
 	} else {
 		fmt.Printf("[INFO] %v: %s\n", entry.Version, entry.Message)
 	}
@@ -134,11 +190,13 @@ func (l *Logger) rotate() error {
 	}
 
 	// Rename current file with timestamp
-	timestamp := time.Now().Format("20060102T150405")
+	timestamp := time.Now().Format(rotatedTimeFormat)
 	rotatedPath := fmt.Sprintf("%s.%s", l.filePath, timestamp)
 	if err := os.Rename(l.filePath, rotatedPath); err != nil {
 		// Try to reopen the original file
-		l.LogFile, _ = os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if f, openErr := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); openErr == nil {
+			l.LogFile, l.out = f, f
+		}
 		return fmt.Errorf("failed to rename log file: %w", err)
 	}
 
@@ -148,10 +206,45 @@ func (l *Logger) rotate() error {
 		return fmt.Errorf("failed to create new log file: %w", err)
 	}
 
-	l.LogFile = f
+	l.LogFile, l.out = f, f
+
+	if l.MaxBackups > 0 {
+		if err := pruneBackups(l.filePath, l.MaxBackups); err != nil {
+			fmt.Fprintf(os.Stderr, "[LOGGER ERROR] Failed to remove old rotated logs: %v\n", err)
+		}
+	}
+
+	if l.CompressRotated {
+		l.compressing.Add(1)
+		go func() {
+			defer l.compressing.Done()
+			if err := compressFile(rotatedPath); err != nil {
+				fmt.Fprintf(os.Stderr, "[LOGGER ERROR] Failed to compress %s, keeping it uncompressed: %v\n", rotatedPath, err)
+			}
+		}()
+	}
 	return nil
 }
 
+// SetLevel sets the minimum level that is logged (DEBUG, INFO, WARN or
+// ERROR, case-insensitive)
+func (l *Logger) SetLevel(level string) error {
+	level = strings.ToUpper(level)
+	if _, ok := levelRank[level]; !ok {
+		return fmt.Errorf("unknown log level %q (use DEBUG, INFO, WARN or ERROR)", level)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.MinLevel = level
+	return nil
+}
+
+// LogDebug is a convenience method for DEBUG level logs, which are only
+// written when the level is set to DEBUG
+func (l *Logger) LogDebug(version, message string) {
+	l.Log(LogEntry{Level: LevelDebug, Version: version, Message: message})
+}
+
 // LogInfo is a convenience method for INFO level logs
 func (l *Logger) LogInfo(version, message string) {
 	l.Log(LogEntry{Level: "INFO", Version: version, Message: message})
@@ -167,8 +260,11 @@ func (l *Logger) LogWarn(version, message string) {
 	l.Log(LogEntry{Level: "WARN", Version: version, Message: message})
 }
 
-// Close closes the log file
+// Close closes the log file (writers passed to NewWithWriter are left open),
+// after waiting for rotated files still being compressed
 func (l *Logger) Close() {
+	l.compressing.Wait()
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
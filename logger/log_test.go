@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"api_diff_checker/clock"
+)
+
+func TestNewWithWriterWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithWriter(&buf, false)
+	frozen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.Clock = clock.Fixed(frozen)
+
+	l.LogInfo("v1", "executed")
+	l.LogError("v2", "failed", "connection refused")
+	l.LogDebug("v1", "suppressed below INFO")
+	l.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q is not a JSON entry: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e.Level != LevelInfo || e.Version != "v1" || e.Message != "executed" || !e.Timestamp.Equal(frozen) {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := entries[1]; e.Level != LevelError || e.ErrorDetails != "connection refused" {
+		t.Errorf("second entry = %+v", e)
+	}
+}
+
+func TestNewWithWriterKeepsLinesIntact(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithWriter(&buf, false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				l.LogInfo(fmt.Sprintf("v%d", i), "executed")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines++
+		if !json.Valid(scanner.Bytes()) {
+			t.Fatalf("interleaved line %q", scanner.Text())
+		}
+	}
+	if lines != 200 {
+		t.Errorf("got %d lines, want 200", lines)
+	}
+}
@@ -5,76 +5,471 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
+	"api_diff_checker/clock"
+	"api_diff_checker/comparator"
 	"api_diff_checker/config"
 	"api_diff_checker/core"
+	"api_diff_checker/executor"
 	"api_diff_checker/logger"
+	"api_diff_checker/notify"
+	"api_diff_checker/reporter"
 	myServer "api_diff_checker/server" // Will create this package next
 	"api_diff_checker/storage"
 )
 
 func main() {
 	webMode := flag.Bool("web", false, "Start web server mode")
+	apiKey := flag.String("api-key", "", "Require this bearer token on the web server's run and result endpoints (default $API_KEY)")
+	healthEngine := flag.String("health-engine", config.EngineCurl, "Execution engine the web server's /api/health requires: curl or native")
+	requireCurl := flag.Bool("require-curl", false, "Fail at startup if curl is not installed (not needed with \"engine\": \"native\")")
+	contentAddressed := flag.Bool("content-addressed", false, "Name response files by content hash so identical responses are stored once")
+	retain := flag.Int("retain", 0, "After the run, keep only the N most recent executions per command in responses/ (0 = keep all)")
+	filenameTemplate := flag.String("filename-template", "", "Name response files with this template, e.g. \"{testcase}_{version}_{hash8}_{ts}\" (default \""+storage.DefaultFilenameTemplate+"\")")
+	compress := flag.Bool("compress", false, "Gzip stored response files (.json.gz)")
+	noFormat := flag.Bool("no-format", false, "Store XML and HTML responses as received instead of re-indenting them")
+	failOnSkip := flag.Bool("fail-on-skip", false, "Fail the run if any test case skipped a version")
+	logLevel := flag.String("log-level", "INFO", "Minimum level written to execution.log and stdout: DEBUG, INFO, WARN or ERROR")
+	logBackups := flag.Int("log-backups", 0, "Keep at most N rotated execution.log files (0 = keep all)")
+	outDir := flag.String("out-dir", "responses", "Directory for stored responses, the index and run history (also used by --web)")
+	logFile := flag.String("log-file", "execution.log", "Path of the execution log")
+	noRecover := flag.Bool("no-recover", false, "Let panics in command execution crash with a stack trace (debugging)")
+	goldenDir := flag.String("golden", "", "Compare responses against approved golden files in this directory")
+	review := flag.Bool("review", false, "Interactively accept or reject golden mismatches (requires --golden)")
+	acceptAll := flag.Bool("accept-all", false, "Accept every golden mismatch (requires --golden)")
+	rejectAll := flag.Bool("reject-all", false, "Reject every golden mismatch (requires --golden)")
+	archivePath := flag.String("archive", "", "Write a zip archive of the run's responses, diffs and manifest to this path")
+	htmlPath := flag.String("html", "", "Write a standalone HTML diff report to this path")
+	csvPath := flag.String("csv", "", "Write the changed fields of every version pair as CSV to this path")
+	sideBySide := flag.Bool("side-by-side", false, "Print diffs as two columns instead of a unified diff")
+	noColor := flag.Bool("no-color", false, "Disable colored diff output (also disabled by NO_COLOR or when not a terminal)")
+	var only, tags stringList
+	flag.Var(&only, "only", "Run only the test case with this name (repeatable)")
+	flag.Var(&tags, "tag", "Run only test cases with this tag (repeatable)")
+	failFast := flag.Bool("fail-fast", false, "Stop after the first test case with a difference or error (same as \"fail_fast\": true)")
+	dryRun := flag.Bool("dry-run", false, "Print the resolved command of every test case and version without executing anything")
+	contextLines := flag.Int("context", comparator.DefaultContextLines, "Lines of context around each change in text diffs (overrides \"context_lines\")")
+	failOnDiff := flag.Bool("fail-on-diff", false, "Exit 2 when differences are found and 1 when a comparison failed")
+	baselineFile := flag.String("baseline-file", "", "Report only changes that appeared or disappeared since this baseline snapshot")
+	updateBaseline := flag.Bool("update-baseline", false, "Rewrite --baseline-file with the current run's changes")
 	flag.Parse()
 
+	// Subcommands that don't execute anything
+	if args := flag.Args(); len(args) > 0 && args[0] == "diff-stores" {
+		os.Exit(runDiffStores(args[1:]))
+	}
+	if args := flag.Args(); len(args) > 0 && args[0] == "recompare" {
+		os.Exit(runRecompare(args[1:]))
+	}
+	if args := flag.Args(); len(args) > 0 && args[0] == "validate" {
+		os.Exit(runValidate(args[1:]))
+	}
+	if args := flag.Args(); len(args) > 0 && args[0] == "diff" {
+		os.Exit(runDiff(args[1:]))
+	}
+
+	if *requireCurl {
+		if err := executor.RequireTool("curl"); err != nil {
+			log.Fatalf("Startup check failed: %v", err)
+		}
+	}
+
+	// SOURCE_DATE_EPOCH pins timestamps for reproducible artifacts
+	fixedClock, err := clock.FromEnv()
+	if err != nil {
+		log.Fatalf("Invalid environment: %v", err)
+	}
+
+	// Separate --out-dir and --log-file values keep concurrent runs on one
+	// machine apart; check both before anything runs
+	if err := storage.CheckWritable(*outDir); err != nil {
+		log.Fatalf("Invalid --out-dir: %v", err)
+	}
+	if err := storage.CheckWritable(filepath.Dir(*logFile)); err != nil {
+		log.Fatalf("Invalid --log-file: %v", err)
+	}
+
 	// Initialize components common to both modes
-	l, err := logger.New("execution.log", true)
+	l, err := logger.New(*logFile, true)
 	if err != nil {
 		log.Fatalf("Failed to init logger: %v", err)
 	}
 	defer l.Close()
+	if err := l.SetLevel(*logLevel); err != nil {
+		log.Fatalf("Invalid --log-level: %v", err)
+	}
+	l.MaxBackups = *logBackups
+	l.Clock = fixedClock
 
-	store := storage.NewStore("responses")
+	if *filenameTemplate != "" {
+		if err := storage.ValidateFilenameTemplate(*filenameTemplate); err != nil {
+			log.Fatalf("Invalid --filename-template: %v", err)
+		}
+	}
+
+	store := storage.NewStoreWithOptions(*outDir, storage.StoreOptions{
+		ContentAddressed: *contentAddressed,
+		Compress:         *compress,
+		FilenameTemplate: *filenameTemplate,
+		Clock:            fixedClock,
+		Logger:           l,
+	})
+	store.FormatOnSave = !*noFormat
 	engine := core.NewEngine(store, l)
+	engine.NoRecover = *noRecover
 
 	if *webMode {
 		// Web Mode
 		fmt.Println("Starting Web Server on :9876...")
-		if err := myServer.Start(engine); err != nil {
+		key := *apiKey
+		if key == "" {
+			key = os.Getenv("API_KEY")
+		}
+		if *healthEngine != config.EngineCurl && *healthEngine != config.EngineNative {
+			log.Fatalf("Invalid --health-engine: must be %q or %q", config.EngineCurl, config.EngineNative)
+		}
+		if err := myServer.StartWithOptions(engine, myServer.Options{APIKey: key, ExecEngine: *healthEngine}); err != nil {
 			log.Fatalf("Server failed: %v", err)
 		}
 	} else {
 		// CLI Mode
 		args := flag.Args()
 		if len(args) < 1 {
-			fmt.Println("Usage: api_diff_checker <config_file> OR api_diff_checker --web")
+			fmt.Println("Usage: api_diff_checker <config_file> OR api_diff_checker --web OR api_diff_checker diff-stores <dirA> <dirB> OR api_diff_checker recompare <command-hash> <versionA> <versionB> OR api_diff_checker validate <config_file> OR api_diff_checker diff <fileA> <fileB>")
 			os.Exit(1)
 		}
 		configPath := args[0]
 
+		mode, err := goldenModeFromFlags(*goldenDir, *review, *acceptAll, *rejectAll)
+		if err != nil {
+			log.Fatalf("Invalid flags: %v", err)
+		}
+
 		cfg, err := config.Load(configPath)
 		if err != nil {
 			log.Fatalf("Failed to load config: %v", err)
 		}
 
+		if len(only) > 0 || len(tags) > 0 {
+			cfg.Filter = &config.Filter{Names: only, Tags: tags}
+			if _, err := cfg.SelectedTestCases(); err != nil {
+				log.Fatalf("Invalid filter: %v", err)
+			}
+		}
+
+		if *failFast {
+			cfg.FailFast = true
+		}
+		if flagPassed(flag.CommandLine, "context") {
+			if *contextLines < 0 {
+				log.Fatalf("Invalid flags: --context cannot be negative")
+			}
+			cfg.ContextLines = contextLines
+		}
+
+		if *dryRun {
+			os.Exit(printPlan(engine, cfg))
+		}
+
+		if *updateBaseline && *baselineFile == "" {
+			log.Fatalf("Invalid flags: --update-baseline requires --baseline-file")
+		}
+		var baseline *core.Snapshot
+		if *baselineFile != "" {
+			snapshot, err := core.LoadSnapshot(*baselineFile)
+			if err == nil {
+				baseline = &snapshot
+			} else if !os.IsNotExist(err) || !*updateBaseline {
+				log.Fatalf("Failed to load baseline: %v", err)
+			}
+		}
+
 		result, err := engine.Run(cfg)
 		if err != nil {
 			log.Fatalf("Execution failed: %v", err)
 		}
 
 		// Print Results to Console (CLI Output)
-		printResults(result)
-		fmt.Println("\nDone. Check 'responses/' for files and 'execution.log' for logs.")
+		printResults(result, printOptions{sideBySide: *sideBySide, color: colorEnabled(*noColor)})
+		if result.StoppedEarly {
+			fmt.Printf("\nStopped early (%s)\n", result.StopReason)
+		}
+		fmt.Printf("\nDone. Check '%s/' for files and '%s' for logs.\n", *outDir, *logFile)
+
+		if cfg.Notify != nil {
+			if err := notify.Send(cfg.Notify, result); err != nil {
+				fmt.Printf("[WARN] Notification failed: %v\n", err)
+			}
+		}
+
+		if *retain > 0 {
+			cleaned, err := store.CleanByCount(*retain)
+			if err != nil {
+				fmt.Printf("[WARN] Retention cleanup failed: %v\n", err)
+			} else if cleaned > 0 {
+				fmt.Printf("Removed %d old response file(s), keeping %d execution(s) per command\n", cleaned, *retain)
+			}
+		}
+
+		if *archivePath != "" {
+			if err := writeArchive(*archivePath, result, store); err != nil {
+				log.Fatalf("Failed to write archive: %v", err)
+			}
+			fmt.Printf("Archive written to %s\n", *archivePath)
+		}
+
+		if *htmlPath != "" {
+			if err := writeHTMLReport(*htmlPath, result); err != nil {
+				log.Fatalf("Failed to write HTML report: %v", err)
+			}
+			fmt.Printf("HTML report written to %s\n", *htmlPath)
+		}
+
+		if *csvPath != "" {
+			if err := writeCSVReport(*csvPath, result); err != nil {
+				log.Fatalf("Failed to write CSV report: %v", err)
+			}
+			fmt.Printf("CSV report written to %s\n", *csvPath)
+		}
+
+		var drift *core.SnapshotDiff
+		if *baselineFile != "" {
+			current := core.NewSnapshot(result)
+			if baseline != nil {
+				d := core.CompareSnapshots(*baseline, current)
+				drift = &d
+				printDrift(*baselineFile, d)
+			}
+			if *updateBaseline {
+				if baseline != nil {
+					current = core.MergeSnapshots(*baseline, current)
+				}
+				if err := core.SaveSnapshot(*baselineFile, current); err != nil {
+					log.Fatalf("Failed to update baseline: %v", err)
+				}
+				fmt.Printf("Baseline written to %s (%d change(s))\n", *baselineFile, len(current.Changes))
+				// The new baseline accepts this run's changes
+				drift = &core.SnapshotDiff{}
+			}
+		}
+
+		if *goldenDir != "" {
+			golden, err := storage.OpenGoldenStore(*goldenDir)
+			if err != nil {
+				log.Fatalf("Failed to open golden store: %v", err)
+			}
+			unapproved, err := checkGoldens(result, golden, mode, os.Stdin)
+			if err != nil {
+				log.Fatalf("Golden check failed: %v", err)
+			}
+			if unapproved > 0 {
+				fmt.Printf("\n%d response(s) differ from golden and were not approved\n", unapproved)
+				os.Exit(1)
+			}
+		}
+
+		if *failOnSkip && len(result.Skipped) > 0 {
+			fmt.Println("\nVersions were skipped (--fail-on-skip):")
+			for _, skip := range result.Skipped {
+				fmt.Printf("  - %s: %s\n", skip.TestCase, skip.Version)
+			}
+			os.Exit(1)
+		}
+
+		if result.ChecksFailed {
+			fmt.Println("\nOne or more versions failed their success criteria")
+			os.Exit(1)
+		}
+
+		if *failOnDiff {
+			diffs, errs := result.Outcomes()
+			if errs > 0 {
+				fmt.Printf("\n%d comparison(s) failed (--fail-on-diff)\n", errs)
+				os.Exit(1)
+			}
+			if result.AssertionsFailed {
+				fmt.Println("\nOne or more responses failed their expectations (--fail-on-diff)")
+				os.Exit(1)
+			}
+			if result.SchemaDrift {
+				fmt.Println("\nOne or more responses drifted from their schema (--fail-on-diff)")
+				os.Exit(2)
+			}
+			if drift != nil {
+				// Against a baseline only new changes fail the run
+				if len(drift.New) > 0 {
+					fmt.Printf("\n%d new change(s) since the baseline (--fail-on-diff)\n", len(drift.New))
+					os.Exit(2)
+				}
+			} else if diffs > 0 {
+				fmt.Printf("\n%d version pair(s) differ (--fail-on-diff)\n", diffs)
+				os.Exit(2)
+			}
+		}
+
+		if result.ThresholdExceeded {
+			fmt.Printf("\nChanged fields exceeded max_changed_fields_percent (%.1f%%)\n", cfg.MaxChangedFieldsPercent)
+			os.Exit(2)
+		}
 	}
 }
 
-func printResults(result *core.RunResult) {
+// printOptions controls how printResults renders diffs
+type printOptions struct {
+	sideBySide bool // Two-column diffs instead of unified
+	color      bool // ANSI colors for unified diffs
+}
+
+func printResults(result *core.RunResult, opts printOptions) {
 	for _, cmdRes := range result.CommandResults {
+		for _, check := range cmdRes.Checks {
+			if check.Passed {
+				fmt.Printf("\n[PASS] %s (%s) met its success criteria\n", cmdRes.TestCaseName, check.Version)
+				continue
+			}
+			fmt.Printf("\n[FAIL] %s (%s) failed its success criteria:\n", cmdRes.TestCaseName, check.Version)
+			for _, failure := range check.Failures {
+				fmt.Printf("  - %s\n", failure)
+			}
+		}
+		for _, assertion := range cmdRes.Assertions {
+			if assertion.Passed {
+				continue
+			}
+			fmt.Printf("\n[ASSERT] %s (%s) did not meet its expectation:\n", cmdRes.TestCaseName, assertion.Version)
+			for _, failure := range assertion.Failures {
+				fmt.Printf("  - %s\n", failure)
+			}
+		}
+		for _, schemaDiff := range cmdRes.SchemaDiffs {
+			if schemaDiff.Error != "" {
+				fmt.Printf("\n[SCHEMA] %s (%s) could not be compared with %s: %s\n",
+					cmdRes.TestCaseName, schemaDiff.Version, schemaDiff.Schema, schemaDiff.Error)
+				continue
+			}
+			if !schemaDiff.HasDrift() {
+				continue
+			}
+			fmt.Printf("\n[SCHEMA] %s (%s) drifted from %s:\n", cmdRes.TestCaseName, schemaDiff.Version, schemaDiff.Schema)
+			for _, path := range schemaDiff.Undocumented {
+				fmt.Printf("  - undocumented field '%s'\n", path)
+			}
+			for _, path := range schemaDiff.Missing {
+				fmt.Printf("  - missing required field '%s'\n", path)
+			}
+		}
+		for _, stability := range cmdRes.Stability {
+			if stability.Error != "" {
+				fmt.Printf("\n[UNSTABLE] %s (%s) repeat failed: %s\n", cmdRes.TestCaseName, stability.Version, stability.Error)
+			}
+			if !stability.Varied {
+				continue
+			}
+			fmt.Printf("\n[UNSTABLE] %s (%s) varied across %d runs:\n", cmdRes.TestCaseName, stability.Version, stability.Runs)
+			for _, path := range stability.UnstableFields {
+				fmt.Printf("  - %s\n", path)
+			}
+			if len(stability.UnstableFields) > 0 {
+				fmt.Println("  Consider adding these to ignore_paths")
+			}
+		}
+
 		// fmt.Printf("\nCommand: %s\n", cmdRes.Command)
 		// Execution logs already printed by engine via specific fmt.Printf calls?
 		// Actually engine does fmt.Printf for "Executing Command".
 		// We should print diffs here.
 
+		if result.Baseline != "" && len(cmdRes.Diffs) > 0 {
+			fmt.Printf("\n### %s: vs baseline %s ###\n", cmdRes.TestCaseName, result.Baseline)
+		}
+		if cmdRes.MultiError != "" {
+			fmt.Printf("\n=== %s: all versions ===\nError: %s\n", cmdRes.TestCaseName, cmdRes.MultiError)
+		} else if cmdRes.MultiDiff != nil {
+			fmt.Printf("\n=== %s: all versions ===\n", cmdRes.TestCaseName)
+			printMultiDiff(cmdRes.MultiDiff)
+		}
 		for _, diff := range cmdRes.Diffs {
-			fmt.Printf("\n=== Diff between %s and %s ===\n", diff.VersionA, diff.VersionB)
+			if result.Baseline != "" {
+				fmt.Printf("\n=== %s vs baseline %s ===\n", diff.VersionB, diff.VersionA)
+			} else {
+				fmt.Printf("\n=== Diff between %s and %s ===\n", diff.VersionA, diff.VersionB)
+			}
+			if diff.StatusChanged {
+				fmt.Printf("!!! %s\n", comparator.StatusChange(diff.StatusA, diff.StatusB))
+			}
+			if diff.SizeLimited {
+				fmt.Println("!!! Responses were truncated at max_response_bytes; the diff covers only their beginnings")
+			}
+			for _, side := range []struct {
+				version string
+				partial bool
+			}{{diff.VersionA, diff.PartialA}, {diff.VersionB, diff.PartialB}} {
+				if side.partial {
+					fmt.Printf("!!! %s timed out; comparing the partial response received before the timeout\n", side.version)
+				}
+			}
+			if diff.EndpointChanged {
+				fmt.Printf("!!! Ended at different endpoints: %s vs %s\n", diff.FinalURLA, diff.FinalURLB)
+			}
+			if diff.RequestError != "" {
+				fmt.Printf("Request comparison error: %s\n", diff.RequestError)
+			} else if diff.RequestDiff != nil {
+				fmt.Printf("Request: %s\n", diff.RequestDiff.Summary)
+			}
+			if diff.HeaderDiff != nil && diff.HeaderDiff.HasChanges() {
+				fmt.Printf("Headers: %s\n", diff.HeaderDiff.Summary)
+			}
+			if diff.TrailerDiff != nil && diff.TrailerDiff.HasChanges() {
+				fmt.Printf("Trailers: %s\n", diff.TrailerDiff.Summary)
+			}
 			if diff.Error != "" {
 				fmt.Printf("Error: %s\n", diff.Error)
 				continue
 			}
 
-			if diff.DiffResult.Summary != "No top-level changes" {
-				fmt.Println(diff.DiffResult.TextDiff)
+			for _, card := range diff.DiffResult.Cardinality {
+				if card.HasChanges() {
+					fmt.Println(card.Summary())
+				}
+			}
+
+			if diff.DiffResult.Summary != comparator.NoChangesSummary {
+				if diff.DiffResult.ContentTypeMismatch {
+					// The summary says it all; a line diff of unrelated formats is noise
+				} else if cmdRes.MultiDiff != nil {
+					// The all-versions table above shows the changes
+				} else if opts.sideBySide && (diff.OldContent != "" || diff.NewContent != "") {
+					fmt.Print(comparator.SideBySide([]byte(diff.OldContent), []byte(diff.NewContent),
+						diff.VersionA, diff.VersionB, comparator.DefaultSideBySideWidth))
+				} else if opts.color {
+					fmt.Println(colorizeDiff(diff.DiffResult.TextDiff))
+				} else {
+					fmt.Println(diff.DiffResult.TextDiff)
+				}
 				fmt.Printf("Summary: %s\n", diff.DiffResult.Summary)
+				if len(diff.DiffResult.Sections) > 0 {
+					fmt.Printf("By section: %s\n", comparator.SummarizeSections(diff.DiffResult.Sections))
+				}
+				if diff.ExceedsThreshold {
+					fmt.Printf("Changed fields: %.1f%% (above threshold)\n", diff.ChangedPercent)
+				}
+				if len(diff.ExpectedChanges) > 0 {
+					if diff.Expected {
+						fmt.Println("All changes are expected (expected_diffs):")
+					} else {
+						fmt.Println("Expected changes (expected_diffs):")
+					}
+					for _, change := range diff.ExpectedChanges {
+						if change.Reason != "" {
+							fmt.Printf("  - %s [expected: %s]\n", change.Path, change.Reason)
+						} else {
+							fmt.Printf("  - %s [expected]\n", change.Path)
+						}
+					}
+				}
 				// fmt.Printf("JSON Patch:\n%s\n", string(diff.DiffResult.JsonPatch))
 				// Keeping it slightly cleaner for CLI, or uncomment if needed
 			} else {
@@ -82,4 +477,376 @@ func printResults(result *core.RunResult) {
 			}
 		}
 	}
+
+	if result.Summary != nil {
+		printSummary(*result.Summary)
+	}
+}
+
+// printSummary prints the run-level verdict after the per-pair diffs
+func printSummary(summary core.RunSummary) {
+	fmt.Println("\n=== Run Summary ===")
+	fmt.Printf("Test cases: %d (%d changed, %d failed, %d clean)\n",
+		summary.TestCases, summary.Changed, summary.Failed, summary.Clean)
+	if summary.Expected > 0 {
+		fmt.Printf("Expected differences: %d pair(s)\n", summary.Expected)
+	}
+	if summary.ExecutionErrors > 0 {
+		fmt.Printf("Execution errors: %d (%d timed out)\n", summary.ExecutionErrors, summary.TimedOut)
+	}
+	if len(summary.TopFields) > 0 {
+		fmt.Println("Most changed fields:")
+		for _, field := range summary.TopFields {
+			fmt.Printf("  %-40s %d pair(s)\n", field.Path, field.Count)
+		}
+	}
+	if summary.IsClean() {
+		fmt.Println("Verdict: clean")
+	} else {
+		fmt.Println("Verdict: needs review")
+	}
+}
+
+// multiCellWidth truncates values in the all-versions table
+const multiCellWidth = 40
+
+// printMultiDiff prints the fields that differ between versions as a table,
+// one column per version, marking values that differ from the most common one
+func printMultiDiff(multi *comparator.MultiDiffResult) {
+	if !multi.HasChanges() {
+		fmt.Println("No significant differences.")
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "FIELD\t%s\n", strings.Join(multi.Versions, "\t"))
+	for _, field := range multi.Fields {
+		differs := make(map[string]bool, len(field.Differs))
+		for _, version := range field.Differs {
+			differs[version] = true
+		}
+		cells := make([]string, len(multi.Versions))
+		for i, version := range multi.Versions {
+			value, ok := field.Values[version]
+			if !ok {
+				value = "(missing)"
+			} else if len(value) > multiCellWidth {
+				value = value[:multiCellWidth-3] + "..."
+			}
+			if differs[version] {
+				value += " *"
+			}
+			cells[i] = value
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", field.Path, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+	fmt.Printf("Summary: %s (* = differs from the most common value)\n", multi.Summary)
+}
+
+// printDrift prints the changes that appeared or disappeared since the baseline
+func printDrift(path string, drift core.SnapshotDiff) {
+	fmt.Printf("\n=== Changes since baseline %s ===\n", path)
+	if !drift.HasChanges() {
+		fmt.Println("No new or resolved changes.")
+		return
+	}
+	for _, group := range []struct {
+		label   string
+		changes []core.SnapshotChange
+	}{{"New", drift.New}, {"Resolved", drift.Resolved}} {
+		if len(group.changes) == 0 {
+			continue
+		}
+		fmt.Printf("%s (%d):\n", group.label, len(group.changes))
+		for _, c := range group.changes {
+			fmt.Printf("  - %s [%s vs %s] %s (%s)\n", c.TestCase, c.VersionA, c.VersionB, c.Path, c.Kind)
+		}
+	}
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+// printPlan prints the commands a run of cfg would execute, grouped by test
+// case, and returns the exit code: 1 if any command could not be resolved
+func printPlan(engine *core.Engine, cfg *config.Config) int {
+	plan, err := engine.Plan(cfg)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+
+	failed := 0
+	testCase := ""
+	for i, planned := range plan {
+		if i == 0 || planned.TestCase != testCase {
+			testCase = planned.TestCase
+			fmt.Printf("\n--- Test Case: %s ---\n", testCase)
+		}
+		if planned.Error != "" {
+			failed++
+			fmt.Printf("[%s] ERROR: %s\n", planned.Version, planned.Error)
+			continue
+		}
+		fmt.Printf("[%s] %s\n", planned.Version, planned.Command)
+	}
+
+	fmt.Printf("\nDry run (%s engine): %d command(s), nothing was executed\n", engineName(cfg), len(plan))
+	if failed > 0 {
+		fmt.Printf("%d command(s) could not be resolved\n", failed)
+		return 1
+	}
+	return 0
+}
+
+// engineName returns the configured execution engine, defaulting to curl
+func engineName(cfg *config.Config) string {
+	if cfg.Engine == "" {
+		return config.EngineCurl
+	}
+	return cfg.Engine
+}
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// flagPassed reports whether the named flag was set on the command line, so
+// its default doesn't override the config
+func flagPassed(fs *flag.FlagSet, name string) bool {
+	passed := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
+
+// writeHTMLReport writes the HTML report of a run to path
+func writeHTMLReport(path string, result *core.RunResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reporter.WriteHTML(f, result); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeCSVReport writes the CSV change matrix of a run to path
+func writeCSVReport(path string, result *core.RunResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reporter.WriteCSV(f, result); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeArchive writes the zip archive of a run to path
+func writeArchive(path string, result *core.RunResult, store *storage.Store) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reporter.WriteArchive(f, result, store); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runDiffStores implements the diff-stores subcommand and returns the exit code
+func runDiffStores(args []string) int {
+	fs := flag.NewFlagSet("diff-stores", flag.ExitOnError)
+	keysOnly := fs.Bool("keys-only", false, "Compare only JSON structure")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fmt.Println("Usage: api_diff_checker diff-stores [--keys-only] <dirA> <dirB>")
+		return 1
+	}
+
+	result, err := core.DiffStores(fs.Arg(0), fs.Arg(1), comparator.CompareOptions{KeysOnly: *keysOnly})
+	if err != nil {
+		fmt.Printf("diff-stores failed: %v\n", err)
+		return 1
+	}
+
+	for _, entry := range result.Entries {
+		if entry.Status == core.StoreDiffUnchanged {
+			continue
+		}
+		fmt.Printf("\n[%s] %s (%s)\n", strings.ToUpper(entry.Status), entry.Command, entry.Version)
+		if entry.Error != "" {
+			fmt.Printf("Error: %s\n", entry.Error)
+		} else if entry.DiffResult != nil {
+			fmt.Println(entry.DiffResult.TextDiff)
+			fmt.Printf("Summary: %s\n", entry.DiffResult.Summary)
+		}
+	}
+
+	fmt.Printf("\n%d changed, %d added, %d removed, %d unchanged\n",
+		result.Changed, result.Added, result.Removed, result.Unchanged)
+	return 0
+}
+
+// runValidate implements the "validate" subcommand: it checks config files
+// without executing anything, printing one line per error and warning, and
+// returns 1 if any file has errors
+func runValidate(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("Usage: api_diff_checker validate <config_file>...")
+		return 1
+	}
+
+	code := 0
+	for _, path := range args {
+		cfg, err := config.LoadWithOptions(path, config.LoadOptions{SkipValidation: true})
+		if err != nil {
+			fmt.Printf("%s: ERROR %v\n", path, err)
+			code = 1
+			continue
+		}
+
+		validation := cfg.Validate()
+		for _, e := range validation.Errors {
+			fmt.Printf("%s: ERROR %s\n", path, e.Error())
+		}
+		for _, warning := range validation.Warnings {
+			fmt.Printf("%s: WARN %s\n", path, warning)
+		}
+		if !validation.IsValid() {
+			code = 1
+			fmt.Printf("%s: invalid (%d error(s), %d warning(s))\n", path, len(validation.Errors), len(validation.Warnings))
+		} else {
+			fmt.Printf("%s: ok (%d warning(s))\n", path, len(validation.Warnings))
+		}
+	}
+	return code
+}
+
+// runRecompare implements the "recompare" subcommand: it re-diffs the latest
+// stored responses of two versions of a command with the given options,
+// without executing anything
+func runRecompare(args []string) int {
+	fs := flag.NewFlagSet("recompare", flag.ExitOnError)
+	dir := fs.String("dir", "responses", "Response store to read from")
+	keysOnly := fs.Bool("keys-only", false, "Compare only JSON structure")
+	canonicalize := fs.Bool("canonicalize", false, "Diff with sorted object keys")
+	contextLines := fs.Int("context", comparator.DefaultContextLines, "Lines of context around each change in text diffs")
+	var ignore stringList
+	fs.Var(&ignore, "ignore", "Ignore this response path, e.g. data.requestId (repeatable)")
+	fs.Parse(args)
+
+	if fs.NArg() != 3 {
+		fmt.Println("Usage: api_diff_checker recompare [--dir responses] [--keys-only] [--canonicalize] [--ignore path]... <command-hash> <versionA> <versionB>")
+		return 1
+	}
+	for _, path := range ignore {
+		if err := comparator.ValidateIgnorePath(path); err != nil {
+			fmt.Printf("Invalid --ignore: %v\n", err)
+			return 1
+		}
+	}
+
+	store, err := storage.OpenStore(*dir)
+	if err != nil {
+		fmt.Printf("recompare failed: %v\n", err)
+		return 1
+	}
+	engine := core.NewEngine(store, nil)
+
+	if *contextLines < 0 {
+		fmt.Println("Invalid --context: cannot be negative")
+		return 1
+	}
+	opts := comparator.CompareOptions{KeysOnly: *keysOnly, Canonicalize: *canonicalize, IgnorePaths: ignore, ContextLines: contextLines}
+	diff, err := engine.CompareStored(fs.Arg(0), fs.Arg(1), fs.Arg(2), opts)
+	if err != nil {
+		fmt.Printf("recompare failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Println(diff.TextDiff)
+	fmt.Printf("Summary: %s\n", diff.Summary)
+	return 0
+}
+
+// runDiff implements the "diff" subcommand: it compares two response files on
+// disk without a config, store or log. With --fail-on-diff it exits 2 when
+// the files differ; it exits 1 when a file can't be read or compared.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	keysOnly := fs.Bool("keys-only", false, "Compare only JSON structure")
+	canonicalize := fs.Bool("canonicalize", false, "Diff with sorted object keys")
+	contextLines := fs.Int("context", comparator.DefaultContextLines, "Lines of context around each change in text diffs")
+	sideBySide := fs.Bool("side-by-side", false, "Print the diff as two columns instead of a unified diff")
+	noColor := fs.Bool("no-color", false, "Disable colored diff output (also disabled by NO_COLOR or when not a terminal)")
+	failOnDiff := fs.Bool("fail-on-diff", false, "Exit 2 when the files differ")
+	var ignore stringList
+	fs.Var(&ignore, "ignore", "Ignore this response path, e.g. data.requestId (repeatable)")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fmt.Println("Usage: api_diff_checker diff [--keys-only] [--canonicalize] [--ignore path]... [--side-by-side] [--no-color] [--fail-on-diff] <fileA> <fileB>")
+		return 1
+	}
+	for _, path := range ignore {
+		if err := comparator.ValidateIgnorePath(path); err != nil {
+			fmt.Printf("Invalid --ignore: %v\n", err)
+			return 1
+		}
+	}
+	if *contextLines < 0 {
+		fmt.Println("Invalid --context: cannot be negative")
+		return 1
+	}
+
+	nameA, nameB := fs.Arg(0), fs.Arg(1)
+	original, err := os.ReadFile(nameA)
+	if err != nil {
+		fmt.Printf("diff failed: %v\n", err)
+		return 1
+	}
+	modified, err := os.ReadFile(nameB)
+	if err != nil {
+		fmt.Printf("diff failed: %v\n", err)
+		return 1
+	}
+
+	opts := comparator.CompareOptions{KeysOnly: *keysOnly, Canonicalize: *canonicalize, IgnorePaths: ignore, ContextLines: contextLines}
+	diff, err := comparator.CompareWithOptions(original, modified, nameA, nameB, opts)
+	if err != nil {
+		fmt.Printf("diff failed: %v\n", err)
+		return 1
+	}
+
+	// Identical non-JSON files still get a summary, but no text diff
+	if diff.Summary == comparator.NoChangesSummary || !diff.IsJSON && diff.TextDiff == "" {
+		fmt.Println("No significant differences.")
+		return 0
+	}
+	if *sideBySide {
+		fmt.Print(comparator.SideBySide(original, modified, nameA, nameB, comparator.DefaultSideBySideWidth))
+	} else if colorEnabled(*noColor) {
+		fmt.Println(colorizeDiff(diff.TextDiff))
+	} else {
+		fmt.Println(diff.TextDiff)
+	}
+	fmt.Printf("Summary: %s\n", diff.Summary)
+
+	if *failOnDiff {
+		return 2
+	}
+	return 0
 }
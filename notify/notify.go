@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"api_diff_checker/config"
+	"api_diff_checker/core"
+)
+
+// MaxDiffLength caps the text diff included per version pair, keeping
+// payloads within what chat webhooks accept
+const MaxDiffLength = 1500
+
+// Payload is the JSON body posted in the default format
+type Payload struct {
+	TestCases   int        `json:"test_cases"`
+	Differences int        `json:"differences"`
+	Errors      int        `json:"errors"`
+	Affected    []Affected `json:"affected,omitempty"`
+	RunErrors   []string   `json:"run_errors,omitempty"`
+}
+
+// Affected describes one version pair that differed or failed
+type Affected struct {
+	TestCase string `json:"test_case"`
+	VersionA string `json:"version_a"`
+	VersionB string `json:"version_b"`
+	Summary  string `json:"summary,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Diff     string `json:"diff,omitempty"`
+}
+
+// HasFindings reports whether the run found a difference or an error
+func (p *Payload) HasFindings() bool {
+	return p.Differences > 0 || p.Errors > 0 || len(p.RunErrors) > 0
+}
+
+// Build summarizes a run result for a notification
+func Build(result *core.RunResult) *Payload {
+	p := &Payload{TestCases: len(result.CommandResults), RunErrors: result.Errors}
+	for _, cmdRes := range result.CommandResults {
+		for _, d := range cmdRes.Diffs {
+			// Counted as in the run summary and --fail-on-diff
+			a := Affected{TestCase: cmdRes.TestCaseName, VersionA: d.VersionA, VersionB: d.VersionB}
+			switch {
+			case d.Failed():
+				p.Errors++
+				a.Error = d.Error
+				if a.Error == "" {
+					a.Error = "timed out; compared a partial response"
+				}
+			case d.Differs():
+				p.Differences++
+			default:
+				continue
+			}
+			if d.Error == "" && d.DiffResult != nil {
+				a.Summary = d.DiffResult.Summary
+				a.Diff = truncate(d.DiffResult.TextDiff, MaxDiffLength)
+			}
+			p.Affected = append(p.Affected, a)
+		}
+	}
+	return p
+}
+
+// Send posts the run summary to opts.WebhookURL, unless OnlyOnDiff is set
+// and the run found nothing. It gives up after opts.GetTimeout().
+func Send(opts *config.NotifyOptions, result *core.RunResult) error {
+	p := Build(result)
+	if opts.OnlyOnDiff && !p.HasFindings() {
+		return nil
+	}
+
+	var body []byte
+	var err error
+	if opts.Format == config.NotifyFormatSlack {
+		body, err = json.Marshal(map[string]string{"text": slackText(p)})
+	} else {
+		body, err = json.Marshal(p)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.GetTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// slackText renders the payload as a Slack mrkdwn message
+func slackText(p *Payload) string {
+	var b strings.Builder
+	if p.HasFindings() {
+		fmt.Fprintf(&b, ":warning: *API diff check:* %d difference(s), %d error(s) across %d test case(s)\n",
+			p.Differences, p.Errors, p.TestCases)
+	} else {
+		fmt.Fprintf(&b, ":white_check_mark: *API diff check:* all %d test case(s) match\n", p.TestCases)
+	}
+	for _, a := range p.Affected {
+		fmt.Fprintf(&b, "• *%s* (%s vs %s): ", a.TestCase, a.VersionA, a.VersionB)
+		if a.Error != "" {
+			fmt.Fprintf(&b, "error: %s\n", a.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "%s\n", a.Summary)
+		if a.Diff != "" {
+			fmt.Fprintf(&b, "```%s```\n", a.Diff)
+		}
+	}
+	for _, e := range p.RunErrors {
+		fmt.Fprintf(&b, "• %s\n", e)
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most max bytes, marking the cut
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "\n... (truncated)"
+}
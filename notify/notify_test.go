@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/config"
+	"api_diff_checker/core"
+)
+
+// testResult is a run with an unexpected difference, an expected one, a
+// failed pair, a partial response and an unchanged pair
+func testResult() *core.RunResult {
+	changed := &comparator.DiffResult{Summary: "Changed: price", TextDiff: "-  \"price\": 10\n+  \"price\": 12\n"}
+	return &core.RunResult{CommandResults: []core.CommandResult{
+		{TestCaseName: "prices", Diffs: []core.VersionDiff{
+			{VersionA: "v1", VersionB: "v2", DiffResult: changed},
+			{VersionA: "v1", VersionB: "v3", DiffResult: changed, Expected: true},
+		}},
+		{TestCaseName: "users", Diffs: []core.VersionDiff{
+			{VersionA: "v1", VersionB: "v2", Error: "no response from v2"},
+			{VersionA: "v1", VersionB: "v3", DiffResult: changed, PartialB: true},
+			{VersionA: "v2", VersionB: "v3", DiffResult: &comparator.DiffResult{Summary: comparator.NoChangesSummary}},
+		}},
+	}}
+}
+
+func TestBuildCountsLikeTheRun(t *testing.T) {
+	result := testResult()
+	p := Build(result)
+
+	diffs, errs := result.Outcomes()
+	if p.Differences != diffs || p.Errors != errs {
+		t.Errorf("payload has %d differences, %d errors; run has %d, %d", p.Differences, p.Errors, diffs, errs)
+	}
+	if p.Differences != 1 || p.Errors != 2 {
+		t.Errorf("payload has %d differences, %d errors; want 1, 2", p.Differences, p.Errors)
+	}
+	if len(p.Affected) != 3 {
+		t.Fatalf("got %d affected pairs, want 3: %+v", len(p.Affected), p.Affected)
+	}
+	if partial := p.Affected[2]; partial.Error == "" || partial.Summary == "" {
+		t.Errorf("partial pair = %+v, want an error and its summary", partial)
+	}
+}
+
+// webhook records the bodies posted to a test server
+func webhook(t *testing.T) (*httptest.Server, chan []byte) {
+	t.Helper()
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		bodies <- body
+	}))
+	t.Cleanup(srv.Close)
+	return srv, bodies
+}
+
+func TestSendPostsPayload(t *testing.T) {
+	srv, bodies := webhook(t)
+
+	if err := Send(&config.NotifyOptions{WebhookURL: srv.URL}, testResult()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var p Payload
+	if err := json.Unmarshal(<-bodies, &p); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	if p.TestCases != 2 || p.Differences != 1 || p.Errors != 2 || len(p.Affected) != 3 {
+		t.Errorf("payload = %+v", p)
+	}
+	if a := p.Affected[0]; a.TestCase != "prices" || a.VersionB != "v2" || !strings.Contains(a.Diff, "price") {
+		t.Errorf("first affected pair = %+v", a)
+	}
+}
+
+func TestSendOnlyOnDiff(t *testing.T) {
+	srv, bodies := webhook(t)
+	opts := &config.NotifyOptions{WebhookURL: srv.URL, OnlyOnDiff: true}
+
+	clean := &core.RunResult{CommandResults: []core.CommandResult{{TestCaseName: "users", Diffs: []core.VersionDiff{
+		{VersionA: "v1", VersionB: "v2", DiffResult: &comparator.DiffResult{Summary: comparator.NoChangesSummary}},
+	}}}}
+	if err := Send(opts, clean); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Error("notified about a run without findings")
+	}
+
+	if err := Send(opts, testResult()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Error("did not notify about a run with findings")
+	}
+}
+
+func TestSendTruncatesDiffs(t *testing.T) {
+	srv, bodies := webhook(t)
+	long := strings.Repeat("-  \"field\": \"é\"\n", MaxDiffLength)
+	result := &core.RunResult{CommandResults: []core.CommandResult{{TestCaseName: "big", Diffs: []core.VersionDiff{
+		{VersionA: "v1", VersionB: "v2", DiffResult: &comparator.DiffResult{Summary: "Changed: field", TextDiff: long}},
+	}}}}
+
+	if err := Send(&config.NotifyOptions{WebhookURL: srv.URL}, result); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var p Payload
+	if err := json.Unmarshal(<-bodies, &p); err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+	diff := p.Affected[0].Diff
+	if !strings.HasSuffix(diff, "... (truncated)") || len(diff) > MaxDiffLength+len("\n... (truncated)") {
+		t.Errorf("diff of %d bytes not truncated to %d", len(diff), MaxDiffLength)
+	}
+	if strings.ContainsRune(diff, '\uFFFD') {
+		t.Error("truncation split a UTF-8 character")
+	}
+}
+
+func TestSendTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	err := Send(&config.NotifyOptions{WebhookURL: srv.URL, Timeout: 1}, testResult())
+	if err == nil {
+		t.Fatal("Send succeeded against a hung webhook")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Send gave up after %v, want about 1s", elapsed)
+	}
+}
+
+func TestSendReportsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	if err := Send(&config.NotifyOptions{WebhookURL: srv.URL}, testResult()); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Send = %v, want the 500 status reported", err)
+	}
+}
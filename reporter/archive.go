@@ -0,0 +1,126 @@
+package reporter
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"api_diff_checker/core"
+	"api_diff_checker/storage"
+)
+
+// archiveManifest describes the contents of an archive (manifest.json)
+type archiveManifest struct {
+	Generated time.Time             `json:"generated"`
+	Baseline  string                `json:"baseline,omitempty"`
+	Errors    []string              `json:"errors,omitempty"`
+	TestCases []archiveTestCaseInfo `json:"test_cases"`
+}
+
+type archiveTestCaseInfo struct {
+	Name       string                 `json:"name"`
+	Commands   map[string]string      `json:"commands"`
+	Executions []archiveExecutionInfo `json:"executions"`
+	Diffs      []archiveDiffInfo      `json:"diffs"`
+}
+
+type archiveExecutionInfo struct {
+	Version    string `json:"version"`
+	File       string `json:"file,omitempty"` // Entry name within the archive
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type archiveDiffInfo struct {
+	VersionA string `json:"version_a"`
+	VersionB string `json:"version_b"`
+	Summary  string `json:"summary,omitempty"`
+	File     string `json:"file,omitempty"` // Entry name within the archive
+	Error    string `json:"error,omitempty"`
+}
+
+// WriteArchive writes a zip bundle of a run: every version's response under
+// responses/, each version pair's diff as text under diffs/ and a
+// manifest.json describing the run. Entry names are derived from the test
+// case and version names, so they are stable across runs. Response files are
+// read from store (compressed files are stored decompressed).
+func WriteArchive(w io.Writer, result *core.RunResult, store *storage.Store) error {
+	zw := zip.NewWriter(w)
+	manifest := archiveManifest{
+		Generated: time.Now(),
+		Baseline:  result.Baseline,
+		Errors:    result.Errors,
+	}
+
+	for i, cmdRes := range result.CommandResults {
+		dir := fmt.Sprintf("%02d_%s", i+1, storage.SanitizeFilename(cmdRes.TestCaseName))
+		tc := archiveTestCaseInfo{Name: cmdRes.TestCaseName, Commands: cmdRes.Commands}
+
+		for _, info := range cmdRes.ExecInfo {
+			exec := archiveExecutionInfo{Version: info.Version, StatusCode: info.StatusCode, Error: info.Error}
+			if info.File != "" {
+				content, err := storage.ReadResponse(store.GetResponsePath(filepath.Base(info.File)))
+				if err != nil {
+					exec.Error = fmt.Sprintf("response file unavailable: %v", err)
+				} else {
+					exec.File = fmt.Sprintf("responses/%s/%s.json", dir, storage.SanitizeFilename(info.Version))
+					if err := writeZipEntry(zw, exec.File, content); err != nil {
+						return err
+					}
+				}
+			}
+			tc.Executions = append(tc.Executions, exec)
+		}
+
+		for _, d := range cmdRes.Diffs {
+			diff := archiveDiffInfo{VersionA: d.VersionA, VersionB: d.VersionB, Error: d.Error}
+			if d.DiffResult != nil {
+				diff.Summary = d.DiffResult.Summary
+				diff.File = fmt.Sprintf("diffs/%s/%s_vs_%s.diff", dir,
+					storage.SanitizeFilename(d.VersionA), storage.SanitizeFilename(d.VersionB))
+				if err := writeZipEntry(zw, diff.File, []byte(diffText(d))); err != nil {
+					return err
+				}
+			}
+			tc.Diffs = append(tc.Diffs, diff)
+		}
+		manifest.TestCases = append(manifest.TestCases, tc)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeZipEntry(zw, "manifest.json", data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// diffText renders a version pair's diff with its summary as a header
+func diffText(d core.VersionDiff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s vs %s\n# Summary: %s\n", d.VersionA, d.VersionB, d.DiffResult.Summary)
+	if d.StatusChanged {
+		fmt.Fprintf(&sb, "# HTTP status: %d -> %d\n", d.StatusA, d.StatusB)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(d.DiffResult.TextDiff)
+	return sb.String()
+}
+
+// writeZipEntry adds a file to the archive
+func writeZipEntry(zw *zip.Writer, name string, content []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := f.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/core"
+)
+
+// csvHeader names the columns written by WriteCSV
+var csvHeader = []string{"test_case", "version_a", "version_b", "change_kind", "path", "old", "new"}
+
+// WriteCSV writes the change matrix of a run as CSV: one row per changed
+// path of each version pair, from the structured (JSON) changes. old and new
+// hold the values at the path in each response, empty when the path is
+// missing on that side or can't be looked up. Pairs that failed to compare
+// or have no structured changes contribute no rows; the header row is always
+// written.
+func WriteCSV(w io.Writer, result *core.RunResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, cmdRes := range result.CommandResults {
+		for _, d := range cmdRes.Diffs {
+			if d.Error != "" || d.DiffResult == nil || d.DiffResult.Changes == nil {
+				continue
+			}
+			oldDoc, oldOK := decodeJSON(d.OldContent)
+			newDoc, newOK := decodeJSON(d.NewContent)
+
+			for _, group := range []struct {
+				kind  string
+				paths []string
+			}{
+				{comparator.ChangeAdded, d.DiffResult.Changes.Added},
+				{comparator.ChangeRemoved, d.DiffResult.Changes.Removed},
+				{comparator.ChangeChanged, d.DiffResult.Changes.Changed},
+			} {
+				for _, path := range group.paths {
+					row := []string{cmdRes.TestCaseName, d.VersionA, d.VersionB, group.kind, path,
+						csvValue(oldDoc, oldOK, path), csvValue(newDoc, newOK, path)}
+					if err := cw.Write(row); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// decodeJSON decodes a response body, keeping numbers as written
+func decodeJSON(content string) (interface{}, bool) {
+	if content == "" {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader([]byte(content)))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// csvValue renders the value at path in doc: strings as is, other values as
+// JSON, and "" when the path doesn't exist
+func csvValue(doc interface{}, ok bool, path string) string {
+	if !ok {
+		return ""
+	}
+	v, found := comparator.LookupPath(doc, path)
+	if !found {
+		return ""
+	}
+	if s, isString := v.(string); isString {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,259 @@
+package reporter
+
+import (
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/core"
+)
+
+// changePattern matches summary entries such as "Field 'name' changed", the
+// same format the web UI turns into chips
+var changePattern = regexp.MustCompile(`Field '(.+?)' (added|removed|changed|set to null|un-nulled)`)
+
+// htmlReport is the data rendered by reportTemplate
+type htmlReport struct {
+	Generated string
+	Matches   int
+	Diffs     int
+	Errors    int
+	TestCases []htmlTestCase
+}
+
+type htmlTestCase struct {
+	Name    string
+	Changed bool
+	Multi   *htmlMulti // compare_mode "multi" only
+	Diffs   []htmlDiff
+}
+
+// htmlMulti is the all-versions table of a test case
+type htmlMulti struct {
+	Versions []string
+	Rows     []htmlMultiRow
+	Summary  string
+	Error    string
+}
+
+type htmlMultiRow struct {
+	Path  string
+	Cells []htmlMultiCell
+}
+
+type htmlMultiCell struct {
+	Value   string
+	Missing bool
+	Differs bool // Differs from the most common value
+}
+
+type htmlDiff struct {
+	Title   string
+	Status  string // "match", "diff" or "error"
+	Error   string
+	Notes   []string
+	Changes []htmlChange
+	Lines   []htmlLine
+}
+
+type htmlChange struct {
+	Kind string // "added", "removed", "changed" or "other"
+	Text string
+}
+
+type htmlLine struct {
+	Kind string // "add", "del", "hunk", "meta" or ""
+	Text string
+}
+
+// WriteHTML writes a standalone HTML report of a run: one collapsible section
+// per test case with summary badges and a color-coded unified diff. All
+// response content is escaped and the CSS is inline, so the file can be
+// opened directly in a browser.
+func WriteHTML(w io.Writer, result *core.RunResult) error {
+	report := htmlReport{Generated: time.Now().Format(time.RFC1123)}
+
+	for _, cmdRes := range result.CommandResults {
+		tc := htmlTestCase{Name: cmdRes.TestCaseName, Multi: multiTable(cmdRes)}
+		for _, d := range cmdRes.Diffs {
+			diff := htmlDiff{Title: d.VersionA + " → " + d.VersionB}
+			if result.Baseline != "" {
+				diff.Title = d.VersionB + " vs baseline " + d.VersionA
+			}
+			if d.StatusChanged {
+				diff.Notes = append(diff.Notes, comparator.StatusChange(d.StatusA, d.StatusB))
+			}
+
+			switch {
+			case d.Error != "":
+				diff.Status = "error"
+				diff.Error = d.Error
+				report.Errors++
+				tc.Changed = true
+			case d.DiffResult == nil || d.DiffResult.Summary == comparator.NoChangesSummary:
+				diff.Status = "match"
+				report.Matches++
+			default:
+				diff.Status = "diff"
+				diff.Changes = summaryChanges(d.DiffResult.Summary)
+				if tc.Multi == nil {
+					// The all-versions table shows the changes otherwise
+					diff.Lines = diffLines(d.DiffResult.TextDiff)
+				}
+				if len(d.DiffResult.Sections) > 0 {
+					diff.Notes = append(diff.Notes, "By section: "+comparator.SummarizeSections(d.DiffResult.Sections))
+				}
+				report.Diffs++
+				tc.Changed = true
+			}
+			tc.Diffs = append(tc.Diffs, diff)
+		}
+		report.TestCases = append(report.TestCases, tc)
+	}
+
+	return reportTemplate.Execute(w, report)
+}
+
+// multiTable builds the all-versions table of a test case, or nil when the
+// run didn't compare all versions at once
+func multiTable(cmdRes core.CommandResult) *htmlMulti {
+	if cmdRes.MultiError != "" {
+		return &htmlMulti{Error: cmdRes.MultiError}
+	}
+	multi := cmdRes.MultiDiff
+	if multi == nil {
+		return nil
+	}
+
+	table := &htmlMulti{Versions: multi.Versions, Summary: multi.Summary}
+	for _, field := range multi.Fields {
+		row := htmlMultiRow{Path: field.Path}
+		for _, version := range multi.Versions {
+			value, ok := field.Values[version]
+			cell := htmlMultiCell{Value: value, Missing: !ok}
+			for _, v := range field.Differs {
+				cell.Differs = cell.Differs || v == version
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+// summaryChanges splits a diff summary into badges
+func summaryChanges(summary string) []htmlChange {
+	var changes []htmlChange
+	for _, part := range strings.Split(summary, ", ") {
+		kind := "other"
+		if m := changePattern.FindStringSubmatch(part); m != nil {
+			kind = m[2]
+			if kind == comparator.ChangeNulled || kind == comparator.ChangeUnnulled {
+				kind = comparator.ChangeChanged
+			}
+		}
+		changes = append(changes, htmlChange{Kind: kind, Text: part})
+	}
+	return changes
+}
+
+// diffLines classifies the lines of a unified diff for coloring
+func diffLines(textDiff string) []htmlLine {
+	var lines []htmlLine
+	for _, line := range strings.Split(strings.TrimRight(textDiff, "\n"), "\n") {
+		kind := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			kind = "meta"
+		case strings.HasPrefix(line, "@@"):
+			kind = "hunk"
+		case strings.HasPrefix(line, "+"):
+			kind = "add"
+		case strings.HasPrefix(line, "-"):
+			kind = "del"
+		}
+		lines = append(lines, htmlLine{Kind: kind, Text: line})
+	}
+	return lines
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>API Diff Report</title>
+<style>
+  body { font-family: system-ui, -apple-system, sans-serif; color: #1a1a1a; background: #f8f9fa; margin: 0; padding: 2rem; }
+  h1 { font-size: 1.5rem; margin: 0 0 0.25rem; }
+  .generated { color: #6b7280; font-size: 0.875rem; margin-bottom: 1rem; }
+  .badge { display: inline-block; padding: 0.25rem 0.75rem; border-radius: 999px; font-size: 0.875rem; font-weight: 600; margin-right: 0.5rem; }
+  .badge-match { background: #d1fae5; color: #059669; }
+  .badge-diff { background: #fef3c7; color: #d97706; }
+  .badge-error { background: #fee2e2; color: #dc2626; }
+  details.test-case { background: #ffffff; border: 1px solid #e0e0e0; border-radius: 8px; margin-top: 1rem; }
+  details.test-case > summary { cursor: pointer; padding: 0.75rem 1rem; font-weight: 600; }
+  .diff { border-top: 1px solid #e0e0e0; padding: 0.75rem 1rem; }
+  .diff h3 { font-size: 1rem; margin: 0 0 0.5rem; }
+  .note { color: #d97706; font-weight: 600; margin: 0.25rem 0; }
+  .error { color: #dc2626; margin: 0.25rem 0; }
+  .match { color: #059669; margin: 0.25rem 0; }
+  .chip { display: inline-block; padding: 0.125rem 0.5rem; border-radius: 4px; font-size: 0.8125rem; margin: 0 0.25rem 0.25rem 0; background: #f3f4f6; }
+  .chip.added { background: #d1fae5; color: #059669; }
+  .chip.removed { background: #fee2e2; color: #dc2626; }
+  .chip.changed { background: #dbeafe; color: #2563eb; }
+  pre { font-family: ui-monospace, "Courier New", monospace; font-size: 0.8125rem; background: #f8f9fa; border: 1px solid #e0e0e0; border-radius: 4px; padding: 0.5rem 0; overflow-x: auto; }
+  pre span { display: block; padding: 0 0.75rem; white-space: pre; }
+  .add { background: #d1fae5; }
+  .del { background: #fee2e2; }
+  .hunk { color: #2563eb; }
+  .meta { color: #6b7280; }
+  table.multi { border-collapse: collapse; font-size: 0.8125rem; margin: 0.5rem 0; }
+  table.multi th, table.multi td { border: 1px solid #e0e0e0; padding: 0.25rem 0.5rem; text-align: left; font-family: ui-monospace, "Courier New", monospace; }
+  table.multi td.differs { background: #fef3c7; }
+  table.multi td.missing { color: #6b7280; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>API Diff Report</h1>
+<div class="generated">Generated {{.Generated}}</div>
+<div>
+  <span class="badge badge-match">✓ {{.Matches}} Match</span>
+  <span class="badge badge-diff">≠ {{.Diffs}} Diff</span>
+  <span class="badge badge-error">⚠ {{.Errors}} Error</span>
+</div>
+{{range .TestCases}}
+<details class="test-case"{{if .Changed}} open{{end}}>
+  <summary>{{.Name}}</summary>
+  {{with .Multi}}
+  <div class="diff">
+    <h3>All versions</h3>
+    {{if .Error}}<div class="error">Error: {{.Error}}</div>{{else if .Rows}}
+    <table class="multi">
+      <tr><th>Field</th>{{range .Versions}}<th>{{.}}</th>{{end}}</tr>
+      {{range .Rows}}<tr><td>{{.Path}}</td>{{range .Cells}}<td class="{{if .Differs}}differs{{end}}{{if .Missing}} missing{{end}}">{{if .Missing}}missing{{else}}{{.Value}}{{end}}</td>{{end}}</tr>
+      {{end}}
+    </table>
+    <div class="note">{{.Summary}}</div>
+    {{else}}<div class="match">No significant differences</div>{{end}}
+  </div>
+  {{end}}
+  {{range .Diffs}}
+  <div class="diff">
+    <h3>{{.Title}}</h3>
+    {{range .Notes}}<div class="note">{{.}}</div>{{end}}
+    {{if eq .Status "error"}}<div class="error">Error: {{.Error}}</div>{{end}}
+    {{if eq .Status "match"}}<div class="match">No significant differences</div>{{end}}
+    {{if eq .Status "diff"}}
+    <div>{{range .Changes}}<span class="chip {{.Kind}}">{{.Text}}</span>{{end}}</div>
+    <pre>{{range .Lines}}<span class="{{.Kind}}">{{.Text}}</span>{{end}}</pre>
+    {{end}}
+  </div>
+  {{end}}
+</details>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,131 @@
+package schema
+
+import "sort"
+
+// Drift lists where a document's shape departs from a schema, regardless of
+// whether the schema allows it: fields the schema doesn't describe and
+// required fields the document lacks. Paths use [] for array elements, as
+// ignore_paths do, e.g. "items[].discount".
+type Drift struct {
+	Undocumented []string `json:"undocumented,omitempty"`
+	Missing      []string `json:"missing,omitempty"`
+}
+
+// HasDrift reports whether the document departs from the schema
+func (d Drift) HasDrift() bool {
+	return len(d.Undocumented) > 0 || len(d.Missing) > 0
+}
+
+// Compare walks a decoded JSON document alongside the schema. Fields of an
+// object whose schema lists properties are undocumented unless listed (in
+// the schema or any allOf/anyOf/oneOf member) or covered by an
+// additionalProperties schema; objects without listed properties, or with
+// additionalProperties: true, are free-form and not descended.
+func (s *Schema) Compare(doc interface{}) Drift {
+	undocumented := make(map[string]bool)
+	missing := make(map[string]bool)
+	s.compare(doc, "", undocumented, missing)
+	return Drift{Undocumented: sortedKeys(undocumented), Missing: sortedKeys(missing)}
+}
+
+func (s *Schema) compare(v interface{}, path string, undocumented, missing map[string]bool) {
+	s = s.deref()
+	if s == nil {
+		return
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		properties, required, additional := s.objectShape()
+		for _, key := range required {
+			if _, ok := val[key]; !ok {
+				missing[joinPath(path, key)] = true
+			}
+		}
+		freeForm := len(properties) == 0 ||
+			(additional != nil && additional.Allowed && additional.Schema == nil)
+		for key, child := range val {
+			childPath := joinPath(path, key)
+			if sub, ok := properties[key]; ok {
+				sub.compare(child, childPath, undocumented, missing)
+			} else if additional != nil && additional.Schema != nil {
+				additional.Schema.compare(child, childPath, undocumented, missing)
+			} else if !freeForm {
+				undocumented[childPath] = true
+			}
+		}
+
+	case []interface{}:
+		items := s.itemsSchema()
+		for _, item := range val {
+			items.compare(item, path+"[]", undocumented, missing)
+		}
+	}
+}
+
+// objectShape merges the properties of the schema and its allOf, anyOf and
+// oneOf members. Required fields come from the schema and allOf only, since
+// the other combinators don't require every member to match.
+func (s *Schema) objectShape() (map[string]*Schema, []string, *Additional) {
+	properties := make(map[string]*Schema)
+	required := append([]string(nil), s.Required...)
+	additional := s.AdditionalProperties
+	for name, prop := range s.Properties {
+		properties[name] = prop
+	}
+
+	merge := func(members []*Schema, withRequired bool) {
+		for _, member := range members {
+			props, req, add := member.deref().objectShape()
+			for name, prop := range props {
+				if _, ok := properties[name]; !ok {
+					properties[name] = prop
+				}
+			}
+			if withRequired {
+				required = append(required, req...)
+			}
+			if additional == nil {
+				additional = add
+			}
+		}
+	}
+	merge(s.AllOf, true)
+	merge(s.AnyOf, false)
+	merge(s.OneOf, false)
+	return properties, required, additional
+}
+
+// itemsSchema returns the schema for array elements, looking into allOf
+// members when the schema has none of its own
+func (s *Schema) itemsSchema() *Schema {
+	if s.Items != nil {
+		return s.Items
+	}
+	for _, member := range s.AllOf {
+		if items := member.deref().itemsSchema(); items != nil {
+			return items
+		}
+	}
+	return nil
+}
+
+// joinPath appends an object key to a path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// resolver decodes schemas addressed by "#/..." JSON pointers into one
+// document, linking every $ref to its target. Each ref is decoded once, so
+// recursive schemas terminate.
+type resolver struct {
+	root  interface{}
+	cache map[string]*Schema
+}
+
+// resolve returns the schema at ref, a "#" followed by a JSON pointer
+func (r *resolver) resolve(ref string) (*Schema, error) {
+	if s, ok := r.cache[ref]; ok {
+		return s, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %q: only refs within the same file (\"#/...\") are supported", ref)
+	}
+	node, err := lookupPointer(r.root, strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(node)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schema{}
+	r.cache[ref] = s
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid schema at %s: %w", ref, err)
+	}
+	return s, r.link(s)
+}
+
+// link resolves the $refs of s and its subschemas
+func (r *resolver) link(s *Schema) error {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		target, err := r.resolve(s.Ref)
+		if err != nil {
+			return err
+		}
+		s.target = target
+		return nil
+	}
+
+	children := []*Schema{s.Items}
+	for _, child := range s.Properties {
+		children = append(children, child)
+	}
+	if s.AdditionalProperties != nil {
+		children = append(children, s.AdditionalProperties.Schema)
+	}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, child := range children {
+		if err := r.link(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deref follows $refs to the schema that describes the value
+func (s *Schema) deref() *Schema {
+	for s != nil && s.target != nil {
+		s = s.target
+	}
+	return s
+}
+
+// lookupPointer returns the value a JSON pointer ("/components/schemas/User",
+// "" for the whole document) addresses in doc
+func lookupPointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" || pointer == "/" {
+		return doc, nil
+	}
+	node := doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch val := node.(type) {
+		case map[string]interface{}:
+			child, ok := val[token]
+			if !ok {
+				return nil, fmt.Errorf("%s: '%s' not found", pointer, token)
+			}
+			node = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, fmt.Errorf("%s: invalid index '%s'", pointer, token)
+			}
+			node = val[i]
+		default:
+			return nil, fmt.Errorf("%s: '%s' not found", pointer, token)
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,198 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used to check response shapes:
+// type, properties, required, additionalProperties, items, enum, minItems,
+// allOf/anyOf/oneOf and $ref within the same document
+type Schema struct {
+	Type                 TypeList           `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+
+	// target is the schema Ref points to, set by Load
+	target *Schema
+}
+
+// Additional is the value of additionalProperties: a boolean, or a schema
+// that properties not listed in Properties must satisfy
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	a.Schema = &Schema{}
+	return json.Unmarshal(data, a.Schema)
+}
+
+// TypeList holds the allowed JSON types. It accepts a single string or a list.
+type TypeList []string
+
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("type must be a string or list of strings")
+	}
+	*t = list
+	return nil
+}
+
+// Load reads and parses a schema file. The path may end in a JSON pointer
+// fragment selecting a schema inside the file, such as an OpenAPI component
+// ("openapi.json#/components/schemas/User"); "$ref"s to "#/..." are
+// resolved against the same file.
+func Load(path string) (*Schema, error) {
+	file, pointer, _ := strings.Cut(path, "#")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", file, err)
+	}
+
+	r := &resolver{root: root, cache: make(map[string]*Schema)}
+	s, err := r.resolve("#" + pointer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Validate checks a decoded JSON document against the schema and returns
+// one message per violation (empty if the document is valid)
+func (s *Schema) Validate(doc interface{}) []string {
+	var violations []string
+	s.validate(doc, "$", &violations)
+	return violations
+}
+
+func (s *Schema) validate(v interface{}, path string, violations *[]string) {
+	s = s.deref()
+	if s == nil {
+		return
+	}
+	for _, sub := range s.AllOf {
+		sub.validate(v, path, violations)
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(v) {
+		*violations = append(*violations,
+			fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), typeOf(v)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		*violations = append(*violations, fmt.Sprintf("%s: value not in enum", path))
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for _, key := range s.Required {
+			if _, ok := val[key]; !ok {
+				*violations = append(*violations, fmt.Sprintf("%s: missing required field '%s'", path, key))
+			}
+		}
+
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !s.AdditionalProperties.Allowed {
+					*violations = append(*violations, fmt.Sprintf("%s: unexpected field '%s'", path, key))
+				} else if s.AdditionalProperties != nil {
+					s.AdditionalProperties.Schema.validate(val[key], path+"."+key, violations)
+				}
+				continue
+			}
+			child.validate(val[key], path+"."+key, violations)
+		}
+
+	case []interface{}:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			*violations = append(*violations,
+				fmt.Sprintf("%s: expected at least %d items, got %d", path, *s.MinItems, len(val)))
+		}
+		for i, item := range val {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	}
+}
+
+// matches reports whether v has one of the allowed types
+func (t TypeList) matches(v interface{}) bool {
+	actual := typeOf(v)
+	for _, want := range t {
+		if want == actual {
+			return true
+		}
+		if want == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type name of a decoded JSON value
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// inEnum reports whether v equals one of the enum values
+func inEnum(v interface{}, enum []interface{}) bool {
+	b, _ := json.Marshal(v)
+	for _, e := range enum {
+		eb, _ := json.Marshal(e)
+		if string(b) == string(eb) {
+			return true
+		}
+	}
+	return false
+}
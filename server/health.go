@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"api_diff_checker/config"
+	"api_diff_checker/executor"
+	"api_diff_checker/storage"
+)
+
+// Health statuses reported by /api/health
+const (
+	HealthOK        = "ok"
+	HealthUnhealthy = "unhealthy"
+)
+
+// healthResponse is the body of GET /api/health
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Time   string                 `json:"time"`
+	Checks map[string]healthCheck `json:"checks"`
+}
+
+// healthCheck is the outcome of one readiness check
+type healthCheck struct {
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleHealth reports whether the server can run comparisons: the response
+// store must be writable and the execution engine available. It answers 503
+// when a check fails, so load balancers and probes can act on the status code.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{
+		Status: HealthOK,
+		Time:   time.Now().Format(time.RFC3339),
+		Checks: map[string]healthCheck{
+			"store":  s.checkStore(),
+			"engine": s.checkEngine(),
+		},
+	}
+	for _, check := range resp.Checks {
+		if !check.OK {
+			resp.Status = HealthUnhealthy
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status != HealthOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// checkStore verifies the response store's directory exists and is
+// writable, without creating it
+func (s *Server) checkStore() healthCheck {
+	if s.Engine == nil || s.Engine.Store == nil {
+		return healthCheck{Error: "no response store configured"}
+	}
+	dir := s.Engine.Store.BaseDir
+	if err := storage.ProbeWritable(dir); err != nil {
+		return healthCheck{Detail: dir, Error: err.Error()}
+	}
+	return healthCheck{OK: true, Detail: dir}
+}
+
+// checkEngine verifies the configured execution engine can run commands. The
+// native engine and a custom executor need nothing installed; curl must be
+// on PATH.
+func (s *Server) checkEngine() healthCheck {
+	switch {
+	case s.Engine != nil && s.Engine.Executor != nil:
+		return healthCheck{OK: true, Detail: "custom executor"}
+	case s.ExecEngine == config.EngineNative:
+		return healthCheck{OK: true, Detail: config.EngineNative}
+	}
+	if err := executor.RequireTool("curl"); err != nil {
+		return healthCheck{Detail: config.EngineCurl, Error: err.Error()}
+	}
+	return healthCheck{OK: true, Detail: config.EngineCurl}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"api_diff_checker/core"
+	"api_diff_checker/executor"
+	"api_diff_checker/logger"
+	"api_diff_checker/storage"
+)
+
+// newTestServer returns a server whose engine stores responses in dir and
+// never runs a command
+func newTestServer(dir string) *Server {
+	x := executor.ExecutorFunc(func(opts executor.ExecuteOptions) (*executor.ExecutionResult, error) {
+		return &executor.ExecutionResult{Version: opts.Version, Response: []byte(`{}`)}, nil
+	})
+	engine := core.NewEngineWithExecutor(storage.NewStore(dir), logger.NewWithWriter(io.Discard, false), x)
+	return &Server{Engine: engine, runs: newRunRegistry()}
+}
+
+func getHealth(t *testing.T, s *Server) (int, healthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	var resp healthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding health response: %v", err)
+	}
+	return rec.Code, resp
+}
+
+func TestHealthOK(t *testing.T) {
+	code, resp := getHealth(t, newTestServer(t.TempDir()))
+	if code != http.StatusOK || resp.Status != HealthOK {
+		t.Errorf("got %d %q, want 200 %q", code, resp.Status, HealthOK)
+	}
+}
+
+func TestHealthUnwritableStore(t *testing.T) {
+	// A file in the way makes the store directory unusable even for root,
+	// which ignores permission bits
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, resp := getHealth(t, newTestServer(filepath.Join(blocker, "responses")))
+	if code != http.StatusServiceUnavailable || resp.Status != HealthUnhealthy {
+		t.Errorf("got %d %q, want 503 %q", code, resp.Status, HealthUnhealthy)
+	}
+	if check := resp.Checks["store"]; check.OK || check.Error == "" {
+		t.Errorf("store check = %+v, want a failure", check)
+	}
+	if !resp.Checks["engine"].OK {
+		t.Errorf("engine check = %+v, want ok", resp.Checks["engine"])
+	}
+}
+
+func TestHealthMissingStoreNotCreated(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "responses")
+
+	code, resp := getHealth(t, newTestServer(dir))
+	if code != http.StatusServiceUnavailable || resp.Status != HealthUnhealthy {
+		t.Errorf("got %d %q, want 503 %q", code, resp.Status, HealthUnhealthy)
+	}
+	if check := resp.Checks["store"]; check.OK || !strings.Contains(check.Error, "does not exist") {
+		t.Errorf("store check = %+v, want the directory reported missing", check)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("health check created the store directory (stat: %v)", err)
+	}
+}
+
+func TestHealthLeavesNoProbeFile(t *testing.T) {
+	dir := t.TempDir()
+	if code, _ := getHealth(t, newTestServer(dir)); code != http.StatusOK {
+		t.Fatalf("got %d, want 200", code)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("store directory holds %d entries after the health check, want none", len(entries))
+	}
+}
+
+func TestHealthReadOnlyStore(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+
+	code, resp := getHealth(t, newTestServer(dir))
+	if code != http.StatusServiceUnavailable || resp.Status != HealthUnhealthy {
+		t.Errorf("got %d %q, want 503 %q", code, resp.Status, HealthUnhealthy)
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"api_diff_checker/core"
+	"api_diff_checker/storage"
+)
+
+// Pagination defaults for GET /api/runs
+const (
+	DefaultHistoryLimit = 20
+	MaxHistoryLimit     = 100
+)
+
+// HistoryEntry summarizes a saved run in the history list
+type HistoryEntry struct {
+	storage.RunInfo
+	TestCases int `json:"test_cases"`
+	Diffs     int `json:"diffs"`
+	Errors    int `json:"errors"`
+}
+
+// HistoryPage is one page of the run history, newest first
+type HistoryPage struct {
+	Runs   []HistoryEntry `json:"runs"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// saveHistory persists a finished run so it survives restarts. Failures are
+// logged rather than failing the run.
+func (s *Server) saveHistory(result *core.RunResult) {
+	if result == nil {
+		return
+	}
+	if _, err := s.Engine.Store.SaveRun(result); err != nil {
+		fmt.Printf("[WARN] Failed to save run history: %v\n", err)
+	}
+}
+
+// handleHistory lists saved runs (GET /api/runs?limit=&offset=)
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, err := queryInt(r, "limit", DefaultHistoryLimit)
+	if err != nil || limit < 1 || limit > MaxHistoryLimit {
+		s.errorResponse(w, fmt.Sprintf("limit must be between 1 and %d", MaxHistoryLimit), http.StatusBadRequest)
+		return
+	}
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		s.errorResponse(w, "offset must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+
+	runs, err := s.Engine.Store.ListRuns()
+	if err != nil {
+		s.errorResponse(w, "Failed to list runs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	page := HistoryPage{Runs: []HistoryEntry{}, Total: len(runs), Limit: limit, Offset: offset}
+	if offset < len(runs) {
+		end := offset + limit
+		if end > len(runs) {
+			end = len(runs)
+		}
+		for _, info := range runs[offset:end] {
+			entry := HistoryEntry{RunInfo: info}
+			var result core.RunResult
+			if err := s.Engine.Store.LoadRun(info.ID, &result); err == nil {
+				entry.TestCases = len(result.CommandResults)
+				entry.Diffs, entry.Errors = result.Outcomes()
+			}
+			page.Runs = append(page.Runs, entry)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// handleHistoryRun returns the full result of a saved run (GET /api/runs/{id})
+func (s *Server) handleHistoryRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var result core.RunResult
+	if err := s.Engine.Store.LoadRun(r.PathValue("id"), &result); err != nil {
+		if os.IsNotExist(err) {
+			s.errorResponse(w, "Run not found", http.StatusNotFound)
+		} else {
+			s.errorResponse(w, "Failed to load run: "+err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&result)
+}
+
+// queryInt parses an integer query parameter, returning def when it is absent
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	return strconv.Atoi(raw)
+}
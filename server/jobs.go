@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"api_diff_checker/core"
+)
+
+// JobResponse is the status of a job, with its result once it has finished
+type JobResponse struct {
+	RunStatus
+	Result *core.RunResult `json:"result,omitempty"`
+}
+
+// handleJobs submits a config as a background job (POST /api/jobs)
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg, ok := s.decodeConfig(w, r)
+	if !ok {
+		return
+	}
+
+	entry := s.startBackgroundRun(cfg)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(JobResponse{RunStatus: entry.snapshot()})
+}
+
+// handleJob returns (GET) or cancels (DELETE) the job with the given ID
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	entry, ok := s.runs.get(r.PathValue("id"))
+	if !ok {
+		s.errorResponse(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	entry.mu.Lock()
+	if r.Method == http.MethodDelete {
+		if entry.cancel == nil {
+			state := entry.status.State
+			entry.mu.Unlock()
+			s.errorResponse(w, "Job already finished (state: "+state+")", http.StatusConflict)
+			return
+		}
+		entry.status.State = RunCancelled
+		entry.status.Error = "cancelled by client"
+		entry.cancel()
+	}
+	resp := JobResponse{RunStatus: entry.status, Result: entry.result}
+	entry.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
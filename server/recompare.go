@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"api_diff_checker/comparator"
+	"api_diff_checker/core"
+)
+
+// recompareRequest is the body of POST /api/recompare. Options mirror the
+// config keys of the same name.
+type recompareRequest struct {
+	CommandHash   string                `json:"command_hash"`
+	VersionA      string                `json:"version_a"`
+	VersionB      string                `json:"version_b"`
+	KeysOnly      bool                  `json:"keys_only,omitempty"`
+	IgnorePaths   []string              `json:"ignore_paths,omitempty"`
+	ArrayKeys     map[string]string     `json:"array_keys,omitempty"`
+	MaskRules     []comparator.MaskRule `json:"mask_rules,omitempty"`
+	Canonicalize  bool                  `json:"canonicalize,omitempty"`
+	SortArrays    bool                  `json:"sort_arrays,omitempty"`
+	ContextLines  *int                  `json:"context_lines,omitempty"`
+	StripPrefixes []string              `json:"strip_prefixes,omitempty"`
+}
+
+// compareOptions validates the request's options and converts them
+func (req *recompareRequest) compareOptions() (comparator.CompareOptions, error) {
+	for _, path := range req.IgnorePaths {
+		if err := comparator.ValidateIgnorePath(path); err != nil {
+			return comparator.CompareOptions{}, fmt.Errorf("ignore_paths: %w", err)
+		}
+	}
+	if req.ContextLines != nil && *req.ContextLines < 0 {
+		return comparator.CompareOptions{}, fmt.Errorf("context_lines: cannot be negative")
+	}
+	for _, rule := range req.MaskRules {
+		if err := comparator.ValidateMaskRule(rule); err != nil {
+			return comparator.CompareOptions{}, fmt.Errorf("mask_rules: %w", err)
+		}
+	}
+	return comparator.CompareOptions{
+		KeysOnly:      req.KeysOnly,
+		IgnorePaths:   req.IgnorePaths,
+		ArrayKeys:     req.ArrayKeys,
+		MaskRules:     req.MaskRules,
+		Canonicalize:  req.Canonicalize,
+		SortArrays:    req.SortArrays,
+		ContextLines:  req.ContextLines,
+		StripPrefixes: req.StripPrefixes,
+	}, nil
+}
+
+// handleRecompare re-diffs the latest stored responses of two versions of a
+// command with new options, without executing anything (POST /api/recompare)
+func (s *Server) handleRecompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req recompareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.CommandHash == "" || req.VersionA == "" || req.VersionB == "" {
+		s.errorResponse(w, "command_hash, version_a and version_b are required", http.StatusBadRequest)
+		return
+	}
+	opts, err := req.compareOptions()
+	if err != nil {
+		s.errorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := s.Engine.CompareStored(req.CommandHash, req.VersionA, req.VersionB, opts)
+	if err != nil {
+		status := http.StatusBadRequest
+		if core.IsNotStored(err) {
+			status = http.StatusNotFound
+		}
+		s.errorResponse(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"api_diff_checker/config"
+	"api_diff_checker/core"
+	"api_diff_checker/reporter"
+)
+
+// Run states
+const (
+	RunPending   = "pending"
+	RunRunning   = "running"
+	RunDone      = "done"
+	RunFailed    = "failed"
+	RunCancelled = "cancelled"
+)
+
+// Finished runs are kept in memory for RunTTL, then dropped by the cleanup loop
+const (
+	RunTTL             = time.Hour
+	RunCleanupInterval = 5 * time.Minute
+)
+
+// RunStatus is the progress snapshot returned by the status endpoint
+type RunStatus struct {
+	ID          string  `json:"id"`
+	State       string  `json:"state"`
+	Completed   int     `json:"completed"`
+	Total       int     `json:"total"`
+	Percent     float64 `json:"percent"`
+	DiffsSoFar  int     `json:"diffs_so_far"`
+	ErrorsSoFar int     `json:"errors_so_far"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// runEntry tracks a single background run
+type runEntry struct {
+	mu       sync.Mutex
+	status   RunStatus
+	result   *core.RunResult
+	created  time.Time
+	finished time.Time          // Zero while the run is pending or running
+	cancel   context.CancelFunc // Cancels the run's context (nil for finished runs)
+}
+
+// update applies a progress event to the run status
+func (r *runEntry) update(ev core.ProgressEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.Completed = ev.Completed
+	r.status.Total = ev.Total
+	r.status.DiffsSoFar = ev.Diffs
+	r.status.ErrorsSoFar = ev.Errors
+	if ev.Total > 0 {
+		r.status.Percent = float64(ev.Completed) * 100 / float64(ev.Total)
+	}
+}
+
+// finish records the outcome of the run
+func (r *runEntry) finish(result *core.RunResult, runErr error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.result = result
+	r.finished = time.Now()
+	r.cancel = nil
+	switch {
+	case r.status.State == RunCancelled:
+		// Keep the cancelled state; the run error is just the context error
+	case runErr != nil:
+		r.status.State = RunFailed
+		r.status.Error = runErr.Error()
+	default:
+		r.status.State = RunDone
+	}
+}
+
+// snapshot returns a copy of the current status
+func (r *runEntry) snapshot() RunStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// runRegistry holds background runs in memory, keyed by ID
+type runRegistry struct {
+	mu   sync.Mutex
+	runs map[string]*runEntry
+}
+
+func newRunRegistry() *runRegistry {
+	return &runRegistry{runs: make(map[string]*runEntry)}
+}
+
+// create registers a new pending run and returns it
+func (reg *runRegistry) create() *runEntry {
+	id := newRunID()
+	entry := &runEntry{
+		status:  RunStatus{ID: id, State: RunPending},
+		created: time.Now(),
+	}
+
+	reg.mu.Lock()
+	reg.runs[id] = entry
+	reg.mu.Unlock()
+	return entry
+}
+
+// add registers a run that already finished, so its result can be exported
+func (reg *runRegistry) add(result *core.RunResult, runErr error) *runEntry {
+	entry := reg.create()
+	entry.finish(result, runErr)
+	return entry
+}
+
+// get returns the run with the given ID
+func (reg *runRegistry) get(id string) (*runEntry, bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	entry, ok := reg.runs[id]
+	return entry, ok
+}
+
+// cleanup drops runs that finished more than ttl ago and returns how many
+func (reg *runRegistry) cleanup(ttl time.Duration) int {
+	cutoff := time.Now().Add(-ttl)
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	removed := 0
+	for id, entry := range reg.runs {
+		entry.mu.Lock()
+		expired := !entry.finished.IsZero() && entry.finished.Before(cutoff)
+		entry.mu.Unlock()
+		if expired {
+			delete(reg.runs, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// cleanupLoop periodically drops expired runs until stop is closed
+func (reg *runRegistry) cleanupLoop(ttl, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			reg.cleanup(ttl)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// newRunID returns a random hex identifier
+func newRunID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Extremely unlikely; fall back to a time-based ID
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// handleRunAsync starts a run in the background and returns its ID immediately
+func (s *Server) handleRunAsync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg, ok := s.decodeConfig(w, r)
+	if !ok {
+		return
+	}
+
+	entry := s.startBackgroundRun(cfg)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"id": entry.status.ID})
+}
+
+// startBackgroundRun registers a run and executes it in the background
+func (s *Server) startBackgroundRun(cfg *config.Config) *runEntry {
+	// Not tied to the request context: the run outlives the request
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout(cfg, core.DefaultRunTimeout))
+
+	entry := s.runs.create()
+	entry.mu.Lock()
+	entry.cancel = cancel
+	entry.mu.Unlock()
+
+	go func() {
+		defer cancel()
+
+		entry.mu.Lock()
+		if entry.status.State == RunPending {
+			entry.status.State = RunRunning
+		}
+		entry.mu.Unlock()
+
+		result, err := s.Engine.RunWithProgress(ctx, cfg, entry.update)
+		entry.finish(result, err)
+		s.saveHistory(result)
+	}()
+	return entry
+}
+
+// handleRunStatus returns the progress of a background run
+func (s *Server) handleRunStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	entry, ok := s.runs.get(r.PathValue("id"))
+	if !ok {
+		s.errorResponse(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry.snapshot())
+}
+
+// handleRunResult returns the result of a finished background run
+func (s *Server) handleRunResult(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	entry, ok := s.runs.get(r.PathValue("id"))
+	if !ok {
+		s.errorResponse(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	entry.mu.Lock()
+	state, result := entry.status.State, entry.result
+	entry.mu.Unlock()
+
+	if result == nil {
+		s.errorResponse(w, fmt.Sprintf("Run has no result yet (state: %s)", state), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleExport returns a zip archive of a run's responses and diffs
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.URL.Query().Get("run")
+	entry, ok := s.runs.get(id)
+	if !ok {
+		s.errorResponse(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	entry.mu.Lock()
+	state, result := entry.status.State, entry.result
+	entry.mu.Unlock()
+
+	if result == nil {
+		s.errorResponse(w, fmt.Sprintf("Run has no result yet (state: %s)", state), http.StatusConflict)
+		return
+	}
+
+	// Build the archive first so failures can still be reported as JSON
+	var buf bytes.Buffer
+	if err := reporter.WriteArchive(&buf, result, s.Engine.Store); err != nil {
+		s.errorResponse(w, "Failed to build archive: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="run-%s.zip"`, id))
+	w.Write(buf.Bytes())
+}
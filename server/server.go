@@ -2,12 +2,14 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -28,20 +30,39 @@ const (
 
 type Server struct {
 	Engine     *core.Engine
+	APIKey     string // When set, protected endpoints require "Authorization: Bearer <APIKey>"
+	ExecEngine string // Execution engine /api/health checks for ("" = curl)
 	httpServer *http.Server
+	runs       *runRegistry
+}
+
+// Options configures the web server
+type Options struct {
+	// APIKey enables bearer-token authentication on every endpoint that runs
+	// commands or returns results. /api/health and the static UI stay open.
+	APIKey string
+
+	// ExecEngine is the execution engine the submitted configs use
+	// (config.EngineCurl or config.EngineNative, "" = curl). /api/health
+	// reports unhealthy when it is not available.
+	ExecEngine string
 }
 
 func Start(engine *core.Engine) error {
-	s := &Server{Engine: engine}
+	return StartWithOptions(engine, Options{})
+}
 
-	mux := http.NewServeMux()
-	mux.Handle("/", http.FileServer(http.Dir("./static")))
-	mux.HandleFunc("/api/run", s.corsMiddleware(s.handleRun))
-	mux.HandleFunc("/api/health", s.corsMiddleware(s.handleHealth))
+// StartWithOptions starts the web server with the given options
+func StartWithOptions(engine *core.Engine, opts Options) error {
+	s := &Server{Engine: engine, APIKey: opts.APIKey, ExecEngine: opts.ExecEngine, runs: newRunRegistry()}
+
+	if s.APIKey != "" {
+		fmt.Println("API key authentication enabled")
+	}
 
 	s.httpServer = &http.Server{
 		Addr:         ":9876",
-		Handler:      mux,
+		Handler:      s.routes(),
 		ReadTimeout:  ReadTimeout,
 		WriteTimeout: WriteTimeout,
 		IdleTimeout:  IdleTimeout,
@@ -50,6 +71,11 @@ func Start(engine *core.Engine) error {
 	// Handle graceful shutdown
 	go s.handleShutdown()
 
+	// Drop finished runs and jobs after RunTTL
+	stopCleanup := make(chan struct{})
+	defer close(stopCleanup)
+	go s.runs.cleanupLoop(RunTTL, RunCleanupInterval, stopCleanup)
+
 	fmt.Println("Server listening at http://localhost:9876")
 	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("server error: %w", err)
@@ -57,6 +83,25 @@ func Start(engine *core.Engine) error {
 	return nil
 }
 
+// routes returns the handler serving the UI and the API
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.Dir("./static")))
+	mux.HandleFunc("/api/run", s.corsMiddleware(s.authMiddleware(s.handleRun)))
+	mux.HandleFunc("/api/run/async", s.corsMiddleware(s.authMiddleware(s.handleRunAsync)))
+	mux.HandleFunc("/api/run/stream", s.corsMiddleware(s.authMiddleware(s.handleRunStream)))
+	mux.HandleFunc("/api/run/{id}/status", s.corsMiddleware(s.authMiddleware(s.handleRunStatus)))
+	mux.HandleFunc("/api/run/{id}/result", s.corsMiddleware(s.authMiddleware(s.handleRunResult)))
+	mux.HandleFunc("/api/jobs", s.corsMiddleware(s.authMiddleware(s.handleJobs)))
+	mux.HandleFunc("/api/jobs/{id}", s.corsMiddleware(s.authMiddleware(s.handleJob)))
+	mux.HandleFunc("/api/runs", s.corsMiddleware(s.authMiddleware(s.handleHistory)))
+	mux.HandleFunc("/api/runs/{id}", s.corsMiddleware(s.authMiddleware(s.handleHistoryRun)))
+	mux.HandleFunc("/api/export", s.corsMiddleware(s.authMiddleware(s.handleExport)))
+	mux.HandleFunc("/api/recompare", s.corsMiddleware(s.authMiddleware(s.handleRecompare)))
+	mux.HandleFunc("/api/health", s.corsMiddleware(s.handleHealth))
+	return mux
+}
+
 func (s *Server) handleShutdown() {
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
@@ -79,8 +124,9 @@ func (s *Server) corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Run-Id")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		// Handle preflight requests
@@ -93,12 +139,28 @@ func (s *Server) corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status": "ok",
-		"time":   time.Now().Format(time.RFC3339),
-	})
+// authMiddleware rejects requests without the configured bearer token.
+// It is a no-op when no API key is set.
+func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.APIKey != "" && !validBearer(r.Header.Get("Authorization"), s.APIKey) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api_diff_checker"`)
+			s.errorResponse(w, "Missing or invalid API key", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// validBearer reports whether an Authorization header carries the expected
+// bearer token, comparing in constant time
+func validBearer(header, key string) bool {
+	const prefix = "Bearer "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return false
+	}
+	token := strings.TrimSpace(header[len(prefix):])
+	return subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1
 }
 
 func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +169,36 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cfg, ok := s.decodeConfig(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), runTimeout(cfg, WriteTimeout-time.Second))
+	defer cancel()
+
+	result, err := s.Engine.RunWithContext(ctx, cfg)
+	if err != nil && result == nil {
+		s.errorResponse(w, "Execution failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Keep the result so it can be exported via /api/export?run=<id>
+	entry := s.runs.add(result, err)
+	s.saveHistory(result)
+
+	// Even if there was an error, we might have partial results
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Run-Id", entry.snapshot().ID)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		// Log the error but can't send response at this point
+		fmt.Printf("[ERROR] Failed to encode response: %v\n", err)
+	}
+}
+
+// decodeConfig reads, parses and validates the config in the request body.
+// On failure it writes the error response and returns false.
+func (s *Server) decodeConfig(w http.ResponseWriter, r *http.Request) (*config.Config, bool) {
 	// Limit request body size
 	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
 
@@ -118,25 +210,35 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		} else {
 			s.errorResponse(w, "Failed to read request body: "+err.Error(), http.StatusBadRequest)
 		}
-		return
+		return nil, false
 	}
 
 	if len(body) == 0 {
 		s.errorResponse(w, "Empty request body", http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
-	var cfg config.Config
-	if err := json.Unmarshal(body, &cfg); err != nil {
+	var req runRequest
+	if err := json.Unmarshal(body, &req); err != nil {
 		s.errorResponse(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
-		return
+		return nil, false
+	}
+	cfg := req.Config
+
+	if len(req.Only) > 0 {
+		if unknown := unknownTestCases(&cfg, req.Only); len(unknown) > 0 {
+			s.errorResponse(w, fmt.Sprintf("Unknown test case(s) in only: %s (valid: %s)",
+				strings.Join(unknown, ", "), strings.Join(testCaseNames(&cfg), ", ")), http.StatusBadRequest)
+			return nil, false
+		}
+		cfg.Filter = &config.Filter{Names: req.Only}
 	}
 
 	// Validate config
 	validation := cfg.Validate()
 	if !validation.IsValid() {
 		s.errorResponse(w, "Validation failed: "+validation.Error(), http.StatusBadRequest)
-		return
+		return nil, false
 	}
 
 	// Log warnings if any
@@ -144,31 +246,54 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		fmt.Printf("[WARN] Config: %s\n", warning)
 	}
 
-	// Create context with timeout based on number of commands and versions
-	// Allow more time for larger configurations
-	estimatedTime := time.Duration(len(cfg.Commands)*len(cfg.Versions)) * cfg.GetTimeout()
-	if estimatedTime < time.Minute {
-		estimatedTime = time.Minute
-	}
-	if estimatedTime > WriteTimeout {
-		estimatedTime = WriteTimeout - time.Second
-	}
+	return &cfg, true
+}
 
-	ctx, cancel := context.WithTimeout(r.Context(), estimatedTime)
-	defer cancel()
+// runRequest is the body accepted by the run endpoints: a config plus
+// request-only options
+type runRequest struct {
+	config.Config
 
-	result, err := s.Engine.RunWithContext(ctx, &cfg)
-	if err != nil && result == nil {
-		s.errorResponse(w, "Execution failed: "+err.Error(), http.StatusInternalServerError)
-		return
+	// Only runs just the test cases with these names (like the CLI --only)
+	Only []string `json:"only,omitempty"`
+}
+
+// testCaseNames returns the names of a config's test cases in order
+func testCaseNames(cfg *config.Config) []string {
+	var names []string
+	for _, tc := range cfg.GetTestCases() {
+		names = append(names, tc.Name)
 	}
+	return names
+}
 
-	// Even if there was an error, we might have partial results
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(result); err != nil {
-		// Log the error but can't send response at this point
-		fmt.Printf("[ERROR] Failed to encode response: %v\n", err)
+// unknownTestCases returns the names that match no test case of the config
+func unknownTestCases(cfg *config.Config, names []string) []string {
+	known := make(map[string]bool)
+	for _, name := range testCaseNames(cfg) {
+		known[name] = true
+	}
+	var unknown []string
+	for _, name := range names {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// runTimeout estimates how long a run may take based on the number of
+// commands and versions, allowing more time for larger configurations.
+// The result is at least one minute and at most max.
+func runTimeout(cfg *config.Config, max time.Duration) time.Duration {
+	estimatedTime := time.Duration(len(cfg.GetTestCases())*len(cfg.Versions)) * cfg.GetTimeout()
+	if estimatedTime < time.Minute {
+		estimatedTime = time.Minute
+	}
+	if estimatedTime > max {
+		estimatedTime = max
 	}
+	return estimatedTime
 }
 
 func (s *Server) errorResponse(w http.ResponseWriter, message string, status int) {
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"api_diff_checker/core"
+	"api_diff_checker/executor"
+	"api_diff_checker/logger"
+	"api_diff_checker/storage"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	s := newTestServer(t.TempDir())
+	s.APIKey = "secret-key"
+	handler := s.routes()
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{"protected without token", "/api/runs", "", http.StatusUnauthorized},
+		{"protected with wrong token", "/api/runs", "Bearer wrong-key", http.StatusUnauthorized},
+		{"protected with token", "/api/runs", "Bearer secret-key", http.StatusOK},
+		{"health without token", "/api/health", "", http.StatusOK},
+		{"health with token", "/api/health", "Bearer secret-key", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d: %s", tt.path, rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without a WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+// onlyRequest is a run of three test cases limited to B
+const onlyRequest = `{
+	"versions": {"v1": "http://v1.example", "v2": "http://v2.example"},
+	"test_cases": [
+		{"name": "A", "commands": {"v1": "curl {{BASE_URL}}/a", "v2": "curl {{BASE_URL}}/a"}},
+		{"name": "B", "commands": {"v1": "curl {{BASE_URL}}/b", "v2": "curl {{BASE_URL}}/b"}},
+		{"name": "C", "commands": {"v1": "curl {{BASE_URL}}/c", "v2": "curl {{BASE_URL}}/c"}}
+	],
+	"only": %s
+}`
+
+func TestRunOnly(t *testing.T) {
+	var mu sync.Mutex
+	var ran []string
+	x := executor.ExecutorFunc(func(opts executor.ExecuteOptions) (*executor.ExecutionResult, error) {
+		mu.Lock()
+		ran = append(ran, opts.Command)
+		mu.Unlock()
+		return &executor.ExecutionResult{Command: opts.Command, Version: opts.Version, Response: []byte(`{}`), StatusCode: 200}, nil
+	})
+	s := &Server{
+		Engine: core.NewEngineWithExecutor(storage.NewStore(t.TempDir()), logger.NewWithWriter(io.Discard, false), x),
+		runs:   newRunRegistry(),
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleRun(rec, httptest.NewRequest(http.MethodPost, "/api/run", strings.NewReader(fmt.Sprintf(onlyRequest, `["B"]`))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/run = %d: %s", rec.Code, rec.Body)
+	}
+	var result core.RunResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(result.CommandResults) != 1 || result.CommandResults[0].TestCaseName != "B" {
+		t.Errorf("got %d command results, want only test case B", len(result.CommandResults))
+	}
+	for _, command := range ran {
+		if !strings.HasSuffix(command, "/b") {
+			t.Errorf("ran %q, want only test case B's commands", command)
+		}
+	}
+	if len(ran) != 2 {
+		t.Errorf("ran %d commands, want 2", len(ran))
+	}
+}
+
+func TestRunOnlyUnknownTestCase(t *testing.T) {
+	s := newTestServer(t.TempDir())
+
+	rec := httptest.NewRecorder()
+	s.handleRun(rec, httptest.NewRequest(http.MethodPost, "/api/run", strings.NewReader(fmt.Sprintf(onlyRequest, `["B", "D"]`))))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("POST /api/run = %d, want 400", rec.Code)
+	}
+	var resp map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decoding error: %v", err)
+	}
+	if want := "Unknown test case(s) in only: D (valid: A, B, C)"; resp["error"] != want {
+		t.Errorf("error = %q, want %q", resp["error"], want)
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"api_diff_checker/core"
+)
+
+// handleRunStream runs a config and streams progress as Server-Sent Events:
+// a "progress" event per engine ProgressEvent, then a final "result" event
+// with the full RunResult (or an "error" event). The config is read from the
+// POST body, or from the "config" query parameter for GET (EventSource).
+// Closing the connection cancels the run.
+func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodGet:
+		r.Body = io.NopCloser(strings.NewReader(r.URL.Query().Get("config")))
+	default:
+		s.errorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.errorResponse(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	cfg, ok := s.decodeConfig(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(event string, v interface{}) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			fmt.Printf("[ERROR] Failed to encode %s event: %v\n", event, err)
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	// The request context is cancelled when the client disconnects
+	ctx, cancel := context.WithTimeout(r.Context(), runTimeout(cfg, WriteTimeout-time.Second))
+	defer cancel()
+
+	result, err := s.Engine.RunWithProgress(ctx, cfg, func(ev core.ProgressEvent) {
+		send("progress", ev)
+	})
+	if r.Context().Err() != nil {
+		// Client is gone; nothing left to send
+		return
+	}
+	if err != nil && result == nil {
+		send("error", map[string]string{"error": "Execution failed: " + err.Error()})
+		return
+	}
+
+	// Even if there was an error, we might have partial results
+	entry := s.runs.add(result, err)
+	s.saveHistory(result)
+	payload := map[string]interface{}{"id": entry.snapshot().ID, "result": result}
+	if err != nil {
+		payload["error"] = err.Error()
+	}
+	send("result", payload)
+}
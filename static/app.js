@@ -239,18 +239,7 @@ async function runCheck() {
   resultsSummary.innerHTML = "";
 
   try {
-    const response = await fetch("/api/run", {
-      method: "POST",
-      headers: { "Content-Type": "application/json" },
-      body: JSON.stringify(config),
-    });
-
-    if (!response.ok) {
-      const errText = await response.text();
-      throw new Error(errText || "Server Error");
-    }
-
-    const data = await response.json();
+    const data = await runStreamWithProgress(config);
     renderResults(data);
     resultsPanel.classList.remove("hidden");
 
@@ -264,7 +253,108 @@ async function runCheck() {
   } finally {
     runBtn.classList.remove("loading");
     runBtn.disabled = false;
+    document.getElementById("run-progress").classList.add("hidden");
+  }
+}
+
+// fetch wrapper that sends the stored API key, if any. When the server
+// answers 401 it asks for the key once, stores it and retries.
+async function apiFetch(url, options = {}) {
+  const send = () => {
+    const headers = { ...(options.headers || {}) };
+    const key = localStorage.getItem("apiKey");
+    if (key) headers["Authorization"] = `Bearer ${key}`;
+    return fetch(url, { ...options, headers });
+  };
+
+  const resp = await send();
+  if (resp.status !== 401) return resp;
+
+  const key = prompt("This server requires an API key:");
+  if (!key) return resp;
+  localStorage.setItem("apiKey", key.trim());
+  return send();
+}
+
+// Runs the config over the streaming endpoint and drives the progress bar
+// from its Server-Sent Events. Resolves with the final run result.
+async function runStreamWithProgress(config) {
+  const progress = document.getElementById("run-progress");
+  const bar = document.getElementById("run-progress-bar");
+  const label = document.getElementById("run-progress-label");
+  bar.style.width = "0%";
+  label.textContent = "Starting...";
+  progress.classList.remove("hidden");
+
+  const resp = await apiFetch("/api/run/stream", {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(config),
+  });
+  if (!resp.ok) {
+    const errText = await resp.text();
+    throw new Error(errText || "Server Error");
+  }
+
+  const reader = resp.body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = "";
+
+  while (true) {
+    const { value, done } = await reader.read();
+    if (done) break;
+    buffer += decoder.decode(value, { stream: true });
+
+    // Events are separated by a blank line
+    let sep;
+    while ((sep = buffer.indexOf("\n\n")) !== -1) {
+      const { event, data } = parseSSE(buffer.slice(0, sep));
+      buffer = buffer.slice(sep + 2);
+
+      if (event === "progress") {
+        updateProgress(bar, label, data);
+      } else if (event === "result") {
+        return data.result;
+      } else if (event === "error") {
+        throw new Error(data.error || "Run failed");
+      }
+    }
+  }
+  throw new Error("Connection closed before the run finished");
+}
+
+// Parses one Server-Sent Event block into its name and JSON data
+function parseSSE(block) {
+  let event = "message";
+  const dataLines = [];
+  block.split("\n").forEach((line) => {
+    if (line.startsWith("event: ")) event = line.slice(7);
+    else if (line.startsWith("data: ")) dataLines.push(line.slice(6));
+  });
+  return { event, data: JSON.parse(dataLines.join("\n") || "null") };
+}
+
+// Updates the progress bar and label from a progress event
+function updateProgress(bar, label, ev) {
+  const percent = ev.total > 0 ? (ev.completed * 100) / ev.total : 0;
+  bar.style.width = `${percent}%`;
+
+  let detail = "";
+  if (ev.type === "version_executed") {
+    detail = ` — ${ev.test_case}: ran ${ev.version}`;
+  } else if (ev.type === "diff_completed") {
+    detail = ` — ${ev.test_case}: compared ${ev.version_a} vs ${ev.version_b}`;
   }
+  label.textContent = `${ev.completed} / ${ev.total} (${Math.round(
+    percent
+  )}%)${detail}`;
+}
+
+// Reports whether a header diff lists any added, removed or changed header
+function hasHeaderChanges(headerDiff) {
+  return ["added", "removed", "changed"].some(
+    (kind) => (headerDiff[kind] || []).length > 0
+  );
 }
 
 function renderResults(data) {
@@ -279,7 +369,10 @@ function renderResults(data) {
     const diffs = res.diffs || [];
     const hasError = diffs.some((d) => d.error);
     const hasDiffs = diffs.some(
-      (d) => d.diff_result && d.diff_result.summary !== "No top-level changes"
+      (d) =>
+        d.diff_result &&
+        d.diff_result.summary !== "No top-level changes" &&
+        !d.expected
     );
 
     if (hasError) errorCount++;
@@ -337,6 +430,54 @@ function renderResults(data) {
     const body = document.createElement("div");
     body.className = "result-card-body";
 
+    // Failed expectations (contract checks) per version
+    (res.assertions || [])
+      .filter((assertion) => !assertion.passed)
+      .forEach((assertion) => {
+        const assertDiv = document.createElement("div");
+        assertDiv.className = "assertion-failure";
+        assertDiv.innerHTML = `<strong>${escapeHtml(
+          assertion.version
+        )}</strong> failed expectations: ${escapeHtml(
+          (assertion.failures || []).join("; ")
+        )}`;
+        body.appendChild(assertDiv);
+      });
+
+    // Responses that drifted from their schema
+    (res.schema_diffs || [])
+      .filter((schemaDiff) => schemaDiff.error || (schemaDiff.undocumented || []).length || (schemaDiff.missing || []).length)
+      .forEach((schemaDiff) => {
+        const problems = schemaDiff.error
+          ? [schemaDiff.error]
+          : (schemaDiff.undocumented || [])
+              .map((path) => `undocumented field '${path}'`)
+              .concat((schemaDiff.missing || []).map((path) => `missing required field '${path}'`));
+        const schemaDiv = document.createElement("div");
+        schemaDiv.className = "assertion-failure";
+        schemaDiv.innerHTML = `<strong>${escapeHtml(
+          schemaDiff.version
+        )}</strong> vs ${escapeHtml(schemaDiff.schema)}: ${escapeHtml(problems.join("; "))}`;
+        body.appendChild(schemaDiv);
+      });
+
+    (res.stability || [])
+      .filter((stability) => stability.varied || stability.error)
+      .forEach((stability) => {
+        const details = stability.varied
+          ? `varied across ${stability.runs} runs` +
+            ((stability.unstable_fields || []).length
+              ? `: ${stability.unstable_fields.join(", ")}`
+              : "")
+          : `repeat failed: ${stability.error}`;
+        const stabilityDiv = document.createElement("div");
+        stabilityDiv.className = "assertion-failure";
+        stabilityDiv.innerHTML = `<strong>${escapeHtml(
+          stability.version
+        )}</strong> ${escapeHtml(details)}`;
+        body.appendChild(stabilityDiv);
+      });
+
     diffs.forEach((diff) => {
       const block = document.createElement("div");
       block.className = "comparison-block";
@@ -355,6 +496,52 @@ function renderResults(data) {
             `;
       block.appendChild(compHeader);
 
+      // Status mismatches are shown even when bodies match
+      if (diff.status_changed) {
+        const statusDiv = document.createElement("div");
+        statusDiv.className = "status-change";
+        statusDiv.textContent = `HTTP status changed: ${diff.status_a} → ${diff.status_b}`;
+        block.appendChild(statusDiv);
+      }
+
+      if (diff.size_limited) {
+        const sizeDiv = document.createElement("div");
+        sizeDiv.className = "status-change";
+        sizeDiv.textContent = "Responses were truncated at max_response_bytes; the diff covers only their beginnings";
+        block.appendChild(sizeDiv);
+      }
+
+      [
+        [diff.version_a, diff.partial_a],
+        [diff.version_b, diff.partial_b],
+      ]
+        .filter(([, partial]) => partial)
+        .forEach(([version]) => {
+          const partialDiv = document.createElement("div");
+          partialDiv.className = "status-change";
+          partialDiv.textContent = `${version} timed out; comparing the partial response received before the timeout`;
+          block.appendChild(partialDiv);
+        });
+
+      if (diff.expected_changes && diff.expected_changes.length > 0) {
+        const expectedDiv = document.createElement("div");
+        expectedDiv.className = "status-change";
+        const paths = diff.expected_changes
+          .map((c) => (c.reason ? `${c.path} (${c.reason})` : c.path))
+          .join(", ");
+        expectedDiv.textContent = diff.expected
+          ? `All changes are expected: ${paths}`
+          : `Expected changes: ${paths}`;
+        block.appendChild(expectedDiv);
+      }
+
+      if (diff.header_diff && diff.header_diff.summary && hasHeaderChanges(diff.header_diff)) {
+        const headerDiv = document.createElement("div");
+        headerDiv.className = "status-change";
+        headerDiv.textContent = diff.header_diff.summary;
+        block.appendChild(headerDiv);
+      }
+
       if (diff.error) {
         const errDiv = document.createElement("div");
         errDiv.className = "error-message";
@@ -369,7 +556,15 @@ function renderResults(data) {
           const changesDiv = document.createElement("div");
           changesDiv.className = "changes-summary";
 
-          const changes = parseChanges(diff.diff_result.summary);
+          let changes = changesFromSet(diff.diff_result.changes);
+          if (changes.length === 0) {
+            changes = parseChanges(diff.diff_result.summary);
+          }
+          if (changes.length === 0) {
+            // Summaries that aren't field changes (empty response,
+            // content-type mismatch, non-JSON content) are shown as is
+            changesDiv.textContent = diff.diff_result.summary;
+          }
           changes.forEach((change) => {
             const chip = document.createElement("span");
             chip.className = `change-chip ${change.type}`;
@@ -458,6 +653,17 @@ function renderResults(data) {
       errorCount > 1 ? "s" : ""
     }</span>`;
   }
+
+  const topFields = (data.summary && data.summary.top_fields) || [];
+  if (topFields.length > 0) {
+    const fields = topFields
+      .slice(0, 3)
+      .map((field) => `${field.path} (${field.count})`)
+      .join(", ");
+    summaryContainer.innerHTML += `<span class="summary-badge badge-diff" title="Fields that changed in the most version pairs">Most changed: ${escapeHtml(
+      fields
+    )}</span>`;
+  }
 }
 
 function parseChanges(summary) {
@@ -467,7 +673,7 @@ function parseChanges(summary) {
   parts.forEach((part) => {
     const addMatch = part.match(/Field '(.+?)' added/);
     const removeMatch = part.match(/Field '(.+?)' removed/);
-    const changeMatch = part.match(/Field '(.+?)' changed/);
+    const changeMatch = part.match(/Field '(.+?)' (changed|set to null|un-nulled)/);
 
     if (addMatch) changes.push({ field: addMatch[1], type: "added" });
     else if (removeMatch)
@@ -479,6 +685,16 @@ function parseChanges(summary) {
   return changes;
 }
 
+// Builds chips from the structured leaf paths of a diff result
+function changesFromSet(set) {
+  if (!set) return [];
+  return [
+    ...(set.added || []).map((field) => ({ field, type: "added" })),
+    ...(set.removed || []).map((field) => ({ field, type: "removed" })),
+    ...(set.changed || []).map((field) => ({ field, type: "modified" })),
+  ];
+}
+
 function getChangeIcon(type) {
   if (type === "added")
     return '<svg viewBox="0 0 24 24" width="12" height="12" fill="none" stroke="currentColor" stroke-width="2"><line x1="12" y1="5" x2="12" y2="19"/><line x1="5" y1="12" x2="19" y2="12"/></svg>';
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteFileAtomicNeverExposesPartialFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	versions := [][]byte{
+		bytes.Repeat([]byte("a"), 1<<20),
+		bytes.Repeat([]byte("b"), 2<<20),
+	}
+	if err := writeFileAtomic(path, versions[0], 0644); err != nil {
+		t.Fatalf("writeFileAtomic: %v", err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Errorf("ReadFile: %v", err)
+				return
+			}
+			if !bytes.Equal(data, versions[0]) && !bytes.Equal(data, versions[1]) {
+				t.Errorf("read a partial file of %d bytes", len(data))
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		if err := writeFileAtomic(path, versions[i%2], 0644); err != nil {
+			t.Fatalf("writeFileAtomic: %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+// responseFiles lists the files in dir other than the index and temporary files
+func responseFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if name := e.Name(); !strings.HasPrefix(name, ".") && !strings.HasPrefix(name, "index.") {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestSpillResponseNeverExposesPartialFile(t *testing.T) {
+	store := NewStore(t.TempDir())
+	head := []byte(`["first chunk",`)
+	pr, pw := io.Pipe()
+
+	type saved struct {
+		path string
+		err  error
+	}
+	result := make(chan saved, 1)
+	go func() {
+		path, _, err := store.spillResponse("curl http://localhost/big", "v1", head, pr, 0, ResponseMeta{})
+		result <- saved{path, err}
+	}()
+
+	// The reader is blocked mid-response: nothing may appear under a final name
+	if _, err := pw.Write([]byte(`"second chunk",`)); err != nil {
+		t.Fatalf("pipe write: %v", err)
+	}
+	if names := responseFiles(t, store.BaseDir); len(names) != 0 {
+		t.Fatalf("response file visible before it was complete: %v", names)
+	}
+
+	pw.Write([]byte(`"last chunk"]`))
+	pw.Close()
+	res := <-result
+	if res.err != nil {
+		t.Fatalf("spillResponse: %v", res.err)
+	}
+	data, err := os.ReadFile(res.path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if want := `["first chunk","second chunk","last chunk"]`; string(data) != want {
+		t.Errorf("stored %q, want %q", data, want)
+	}
+}
+
+func TestSpillResponseRemovesFailedFile(t *testing.T) {
+	store := NewStore(t.TempDir())
+	pr, pw := io.Pipe()
+	pw.CloseWithError(errors.New("connection reset"))
+
+	if _, _, err := store.spillResponse("curl http://localhost/big", "v1", []byte(`["first chunk",`), pr, 0, ResponseMeta{}); err == nil {
+		t.Fatal("expected the read error")
+	}
+	entries, err := os.ReadDir(store.BaseDir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("files left behind after a failed read: %v", entries)
+	}
+}
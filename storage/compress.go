@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// CompressedExt is appended to response files written with StoreOptions.Compress
+const CompressedExt = ".gz"
+
+// ReadResponse reads a stored response file, transparently decompressing
+// gzip files (those ending in CompressedExt)
+func ReadResponse(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasSuffix(path, CompressedExt) {
+		return data, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	return out, nil
+}
+
+// OpenResponse opens a stored response file for streaming, transparently
+// decompressing gzip files as ReadResponse does
+func OpenResponse(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil || !strings.HasSuffix(path, CompressedExt) {
+		return f, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	return gzipFile{Reader: zr, file: f}, nil
+}
+
+// gzipFile reads a gzip file, closing the file with the reader
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+// gzipBytes compresses content with gzip
+func gzipBytes(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultFilenameTemplate reproduces the classic response file names,
+// e.g. vv2_ab12cd34_20240101T120000.json
+const DefaultFilenameTemplate = "v{version}_{hash8}_{ts}"
+
+// filenamePlaceholder matches {name} tokens in a filename template
+var filenamePlaceholder = regexp.MustCompile(`\{([a-z0-9]+)\}`)
+
+// filenameFields are the placeholders a filename template may use
+var filenameFields = map[string]bool{
+	"testcase": true, // Test case name (empty for direct SaveResponse calls)
+	"version":  true,
+	"hash8":    true, // First 8 characters of the command hash
+	"hash":     true,
+	"ts":       true, // Timestamp, e.g. 20240101T120000
+}
+
+// ValidateFilenameTemplate checks that a template only uses known
+// placeholders and produces a non-empty name
+func ValidateFilenameTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return fmt.Errorf("filename template is empty")
+	}
+	for _, m := range filenamePlaceholder.FindAllStringSubmatch(tmpl, -1) {
+		if !filenameFields[m[1]] {
+			return fmt.Errorf("unknown placeholder {%s} in filename template (use {testcase}, {version}, {hash8}, {hash} or {ts})", m[1])
+		}
+	}
+	return nil
+}
+
+// renderFilename expands a filename template (without extension). Every
+// value is sanitized, and so is the result.
+func renderFilename(tmpl, testCase, version, cmdHash string, ts time.Time) string {
+	if tmpl == "" {
+		tmpl = DefaultFilenameTemplate
+	}
+	values := map[string]string{
+		"testcase": testCase,
+		"version":  version,
+		"hash8":    cmdHash[:8],
+		"hash":     cmdHash,
+		"ts":       ts.Format("20060102T150405"),
+	}
+	name := filenamePlaceholder.ReplaceAllStringFunc(tmpl, func(token string) string {
+		return sanitizeFilename(values[token[1:len(token)-1]])
+	})
+	if name = sanitizeFilename(name); name == "" {
+		name = cmdHash[:8]
+	}
+	return name
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// GoldenStore keeps approved ("golden") responses per command and version,
+// tracked by content hash so unchanged responses can be skipped quickly.
+type GoldenStore struct {
+	Dir      string
+	mu       sync.Mutex
+	Manifest GoldenManifest
+}
+
+type GoldenManifest struct {
+	Entries map[string]GoldenEntry `json:"entries"` // Keyed by goldenKey
+}
+
+type GoldenEntry struct {
+	CommandHash string    `json:"command_hash"`
+	CommandRaw  string    `json:"command_raw"`
+	Version     string    `json:"version"`
+	File        string    `json:"file"`
+	ContentHash string    `json:"content_hash"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// OpenGoldenStore loads the golden manifest from dir, starting empty if none exists
+func OpenGoldenStore(dir string) (*GoldenStore, error) {
+	g := &GoldenStore{
+		Dir:      dir,
+		Manifest: GoldenManifest{Entries: make(map[string]GoldenEntry)},
+	}
+
+	data, err := os.ReadFile(g.manifestPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return g, nil
+		}
+		return nil, fmt.Errorf("failed to read golden manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &g.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse golden manifest: %w", err)
+	}
+	if g.Manifest.Entries == nil {
+		g.Manifest.Entries = make(map[string]GoldenEntry)
+	}
+	return g, nil
+}
+
+func (g *GoldenStore) manifestPath() string {
+	return filepath.Join(g.Dir, "golden.json")
+}
+
+// goldenKey identifies a golden response by command hash and version
+func goldenKey(cmdHash, version string) string {
+	return cmdHash + "/" + version
+}
+
+// Lookup returns the golden entry for a command and version
+func (g *GoldenStore) Lookup(command, version string) (GoldenEntry, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	entry, ok := g.Manifest.Entries[goldenKey(hash(command), version)]
+	return entry, ok
+}
+
+// Read returns the content of a golden entry
+func (g *GoldenStore) Read(entry GoldenEntry) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(g.Dir, entry.File))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden file: %w", err)
+	}
+	return data, nil
+}
+
+// Approve writes content as the golden response for a command and version
+func (g *GoldenStore) Approve(command, version string, content []byte) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := os.MkdirAll(g.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create golden directory: %w", err)
+	}
+
+	cmdHash := hash(command)
+	filename := fmt.Sprintf("v%s_%s.golden.json", sanitizeFilename(version), cmdHash[:8])
+	if err := os.WriteFile(filepath.Join(g.Dir, filename), content, 0644); err != nil {
+		return fmt.Errorf("failed to write golden file: %w", err)
+	}
+
+	g.Manifest.Entries[goldenKey(cmdHash, version)] = GoldenEntry{
+		CommandHash: cmdHash,
+		CommandRaw:  command,
+		Version:     version,
+		File:        filename,
+		ContentHash: ContentHash(content),
+		UpdatedAt:   time.Now(),
+	}
+
+	data, err := json.MarshalIndent(g.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal golden manifest: %w", err)
+	}
+	if err := os.WriteFile(g.manifestPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write golden manifest: %w", err)
+	}
+	return nil
+}
+
+// ContentHash returns the hex SHA-256 of content
+func ContentHash(content []byte) string {
+	return hash(string(content))
+}
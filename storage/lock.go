@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// indexLockFile is the file locked around every change to index.json
+const indexLockFile = "index.lock"
+
+// lockIndexLocked takes the cross-process lock on the index and reloads it
+// from disk, so records written by other processes sharing BaseDir since it
+// was loaded are kept when it is saved. The in-memory index is kept if the
+// file is missing or unreadable. The returned function releases the lock.
+// Must be called with mutex held.
+func (s *Store) lockIndexLocked() (func(), error) {
+	unlock, err := lockIndex(s.BaseDir)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := os.ReadFile(filepath.Join(s.BaseDir, "index.json")); err == nil {
+		var index Index
+		if json.Unmarshal(data, &index) == nil {
+			s.Index = index
+		}
+	}
+
+	return unlock, nil
+}
+
+// lockIndex blocks until it holds the index lock of a store directory,
+// creating the directory if needed
+func lockIndex(dir string) (func(), error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, indexLockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock index: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestStoresSharingDirectoryKeepAllRecords(t *testing.T) {
+	dir := t.TempDir()
+	stores := []*Store{NewStore(dir), NewStore(dir)}
+	const perStore = 25
+
+	var wg sync.WaitGroup
+	for s, store := range stores {
+		wg.Add(1)
+		go func(s int, store *Store) {
+			defer wg.Done()
+			for i := 0; i < perStore; i++ {
+				command := fmt.Sprintf("curl http://localhost/%d/%d", s, i)
+				if _, err := store.SaveResponse(command, "v1", []byte(`{"ok": true}`), nil); err != nil {
+					t.Errorf("SaveResponse: %v", err)
+				}
+			}
+		}(s, store)
+	}
+	wg.Wait()
+
+	reloaded := NewStore(dir)
+	if err := reloaded.LoadIndex(); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	for s := range stores {
+		for i := 0; i < perStore; i++ {
+			command := fmt.Sprintf("curl http://localhost/%d/%d", s, i)
+			entry, ok := reloaded.FindByCommand(command)
+			if !ok || len(entry.Executions) != 1 {
+				t.Errorf("record for %q missing from index", command)
+			}
+		}
+	}
+}
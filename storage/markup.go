@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// formatMarkup re-indents an XML or HTML document, one element per line.
+// HTML (by content type or a leading doctype/<html>) is parsed leniently,
+// closing void elements such as <br>. ok is false when content isn't
+// markup or doesn't parse, in which case it should be stored as is.
+func formatMarkup(content []byte, contentType string) ([]byte, bool) {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 || trimmed[0] != '<' {
+		return nil, false
+	}
+
+	html := isHTML(trimmed, contentType)
+	dec := xml.NewDecoder(bytes.NewReader(trimmed))
+	next := dec.RawToken // Keeps namespace prefixes as written
+	if html {
+		// Token applies AutoClose, matching <br> and friends with an end
+		dec.Strict = false
+		dec.AutoClose = xml.HTMLAutoClose
+		dec.Entity = xml.HTMLEntity
+		next = dec.Token
+	}
+
+	var out bytes.Buffer
+	enc := xml.NewEncoder(&out)
+	enc.Indent("", "  ")
+	elements, depth := 0, 0
+	for {
+		tok, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			elements++
+			depth++
+			t.Name = markupName(t.Name, html)
+			attrs := make([]xml.Attr, len(t.Attr))
+			for i, attr := range t.Attr {
+				attrs[i] = xml.Attr{Name: markupName(attr.Name, html), Value: attr.Value}
+			}
+			t.Attr = attrs
+			tok = t
+		case xml.EndElement:
+			depth--
+			t.Name = markupName(t.Name, html)
+			tok = t
+		case xml.CharData:
+			// Indentation replaces the whitespace between elements
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+			tok = xml.CharData(bytes.TrimSpace(t))
+		}
+		if err := enc.EncodeToken(tok); err != nil {
+			return nil, false
+		}
+		if depth == 0 {
+			// The encoder doesn't break lines after a prolog or between
+			// top-level nodes
+			if err := enc.Flush(); err != nil {
+				return nil, false
+			}
+			out.WriteByte('\n')
+		}
+	}
+	// A document cut short ends without closing its elements
+	if err := enc.Flush(); err != nil || elements == 0 || depth != 0 {
+		return nil, false
+	}
+	return out.Bytes(), true
+}
+
+// markupName folds a raw namespace prefix into the local name, so the
+// encoder writes it back unchanged. HTML names are resolved by Token, so
+// their namespace (a URL) is dropped instead.
+func markupName(name xml.Name, html bool) xml.Name {
+	if name.Space == "" || html {
+		return xml.Name{Local: name.Local}
+	}
+	return xml.Name{Local: name.Space + ":" + name.Local}
+}
+
+// isHTML reports whether markup should be parsed as HTML
+func isHTML(content []byte, contentType string) bool {
+	if strings.Contains(strings.ToLower(contentType), "html") {
+		return true
+	}
+	head := strings.ToLower(string(content[:min(len(content), 64)]))
+	return strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html")
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// clone returns a copy of the record that shares no map with the index, so
+// callers can't change the index without holding the store's lock
+func (rec ExecutionRecord) clone() ExecutionRecord {
+	rec.Headers = maps.Clone(rec.Headers)
+	return rec
+}
+
+// FindByCommand returns a copy of the index entry for a raw command
+func (s *Store) FindByCommand(raw string) (*CommandEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	commandHash := hash(raw)
+	for _, entry := range s.Index.Commands {
+		if entry.CommandHash == commandHash {
+			executions := make([]ExecutionRecord, len(entry.Executions))
+			for i, rec := range entry.Executions {
+				executions[i] = rec.clone()
+			}
+			entry.Executions = executions
+			return &entry, true
+		}
+	}
+	return nil, false
+}
+
+// FindByVersion returns every execution of a version across all commands,
+// oldest first
+func (s *Store) FindByVersion(version string) []ExecutionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []ExecutionRecord
+	for _, entry := range s.Index.Commands {
+		for _, rec := range entry.Executions {
+			if rec.Version == version {
+				records = append(records, rec.clone())
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Timestamp.Before(records[j].Timestamp) })
+	return records
+}
+
+// LatestFor returns the most recent execution of a command hash and version,
+// whether it succeeded or not
+func (s *Store) LatestFor(commandHash, version string) (ExecutionRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest ExecutionRecord
+	found := false
+	for _, entry := range s.Index.Commands {
+		if entry.CommandHash != commandHash {
+			continue
+		}
+		for _, rec := range entry.Executions {
+			if rec.Version == version && (!found || rec.Timestamp.After(latest.Timestamp)) {
+				latest = rec
+				found = true
+			}
+		}
+	}
+	return latest.clone(), found
+}
+
+// CommandHash returns the hash under which a raw command is indexed
+func CommandHash(raw string) string {
+	return hash(raw)
+}
+
+// ErrCommandNotFound is returned by MatchCommandHash when no indexed command matches
+var ErrCommandNotFound = errors.New("no stored command matches")
+
+// MatchCommandHash expands a command hash prefix (such as the 8 characters
+// in response file names) to the full hash of the indexed command it
+// identifies. It fails if the prefix matches no command or several.
+func (s *Store) MatchCommandHash(prefix string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if prefix == "" {
+		return "", fmt.Errorf("%w an empty hash", ErrCommandNotFound)
+	}
+	var matches []string
+	for _, entry := range s.Index.Commands {
+		if entry.CommandHash == prefix {
+			return prefix, nil
+		}
+		if strings.HasPrefix(entry.CommandHash, prefix) {
+			matches = append(matches, entry.CommandHash)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w %q", ErrCommandNotFound, prefix)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("command hash %q is ambiguous (%d matches)", prefix, len(matches))
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"api_diff_checker/clock"
+)
+
+func TestSaveThenQuery(t *testing.T) {
+	store := NewStore(t.TempDir())
+	const command = "curl http://localhost/users"
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	for i, version := range []string{"v1", "v2", "v1"} {
+		store.Options.Clock = clock.Fixed(time.Date(2024, 3, 1, 12, i, 0, 0, time.UTC))
+		meta := ResponseMeta{StatusCode: 200 + i, Headers: headers}
+		if _, err := store.SaveResponseWithMeta(command, version, []byte(`{"id": 1}`), nil, meta); err != nil {
+			t.Fatalf("SaveResponseWithMeta: %v", err)
+		}
+	}
+	store.SaveResponse("curl http://localhost/other", "v1", []byte(`{}`), nil)
+
+	entry, ok := store.FindByCommand(command)
+	if !ok || entry.CommandRaw != command || len(entry.Executions) != 3 {
+		t.Fatalf("FindByCommand = %+v, %v; want 3 executions", entry, ok)
+	}
+	if records := store.FindByVersion("v1"); len(records) != 3 || records[0].StatusCode != 200 || records[1].StatusCode != 202 {
+		t.Errorf("FindByVersion(v1) = %+v, want 3 records oldest first", records)
+	}
+	latest, ok := store.LatestFor(CommandHash(command), "v1")
+	if !ok || latest.StatusCode != 202 || latest.Headers["Content-Type"] != "application/json" {
+		t.Errorf("LatestFor = %+v, %v; want the second v1 execution", latest, ok)
+	}
+	if hash, err := store.MatchCommandHash(CommandHash(command)[:8]); err != nil || hash != CommandHash(command) {
+		t.Errorf("MatchCommandHash = %q, %v", hash, err)
+	}
+	if _, err := store.MatchCommandHash("zzzzzzzz"); err == nil {
+		t.Error("MatchCommandHash matched an unknown hash")
+	}
+}
+
+func TestQueriedRecordsDontShareHeaders(t *testing.T) {
+	store := NewStore(t.TempDir())
+	const command = "curl http://localhost/users"
+	meta := ResponseMeta{Headers: map[string]string{"Content-Type": "application/json"}}
+	if _, err := store.SaveResponseWithMeta(command, "v1", []byte(`{}`), nil, meta); err != nil {
+		t.Fatalf("SaveResponseWithMeta: %v", err)
+	}
+
+	entry, _ := store.FindByCommand(command)
+	entry.Executions[0].Headers["Content-Type"] = "text/plain"
+	store.FindByVersion("v1")[0].Headers["Content-Type"] = "text/plain"
+	latest, _ := store.LatestFor(CommandHash(command), "v1")
+	latest.Headers["Content-Type"] = "text/plain"
+
+	if got := store.Index.Commands[0].Executions[0].Headers["Content-Type"]; got != "application/json" {
+		t.Errorf("index header changed to %q through a query result", got)
+	}
+}
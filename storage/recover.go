@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"api_diff_checker/clock"
+)
+
+// corruptIndexPrefix starts the name of backed-up unreadable indexes,
+// e.g. index.json.corrupt-20240101T120000
+const corruptIndexPrefix = "index.json.corrupt-"
+
+// filenameCaptures are the regular expressions filename placeholders match
+// when parsing response file names back
+var filenameCaptures = map[string]string{
+	"testcase": `(?P<testcase>.*?)`,
+	"version":  `(?P<version>.+?)`,
+	"hash8":    `(?P<hash8>[0-9a-f]{8})`,
+	"hash":     `(?P<hash>[0-9a-f]{64})`,
+	"ts":       `(?P<ts>\d{8}T\d{6})`,
+}
+
+// filenamePattern returns a regular expression matching the response file
+// names a template produces, including the numeric suffix uniqueFilename
+// adds and the compressed extension
+func filenamePattern(tmpl string) (*regexp.Regexp, error) {
+	if tmpl == "" {
+		tmpl = DefaultFilenameTemplate
+	}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range filenamePlaceholder.FindAllStringSubmatchIndex(tmpl, -1) {
+		pattern.WriteString(regexp.QuoteMeta(tmpl[last:loc[0]]))
+		pattern.WriteString(filenameCaptures[tmpl[loc[2]:loc[3]]])
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(tmpl[last:]))
+	pattern.WriteString(`(?:_\d+)?\.json(?:` + regexp.QuoteMeta(CompressedExt) + `)?$`)
+	return regexp.Compile(pattern.String())
+}
+
+// recoverIndexLocked replaces an unreadable index: it backs the file up and
+// rebuilds the index from the response file names in BaseDir, which encode
+// the version and command hash when the filename template includes them.
+// Recovered commands are indexed by the hash in the name (often only its
+// first 8 characters) with no raw command; the next save of the same
+// command completes the entry. Must be called with mutex held.
+func (s *Store) recoverIndexLocked(parseErr error) error {
+	indexPath := filepath.Join(s.BaseDir, "index.json")
+	backup := corruptIndexPrefix + clock.Or(s.Options.Clock).Now().Format("20060102T150405")
+	backupPath := filepath.Join(s.BaseDir, uniqueFilename(s.BaseDir, backup))
+	if err := os.Rename(indexPath, backupPath); err != nil {
+		return fmt.Errorf("failed to parse index (%v) and to back it up: %w", parseErr, err)
+	}
+
+	index, recovered, skipped, err := s.scanResponses()
+	if err != nil {
+		s.Index = Index{Commands: []CommandEntry{}}
+		return fmt.Errorf("failed to parse index (%v, backed up to %s) and to rebuild it: %w", parseErr, backupPath, err)
+	}
+	s.Index = index
+	if err := s.saveIndexLocked(); err != nil {
+		return fmt.Errorf("failed to save rebuilt index: %w", err)
+	}
+
+	message := fmt.Sprintf("Index was unreadable (%v); backed it up to %s and recovered %d execution record(s) from response files",
+		parseErr, backupPath, recovered)
+	if skipped > 0 {
+		message += fmt.Sprintf(" (%d file(s) not matching the filename template were left out)", skipped)
+	}
+	if s.Options.Logger != nil {
+		s.Options.Logger.LogWarn("storage", message)
+	} else {
+		fmt.Printf("[WARN] %s\n", message)
+	}
+	return nil
+}
+
+// scanResponses builds an index from the response files in BaseDir. It
+// returns how many records were recovered and how many files were skipped.
+func (s *Store) scanResponses() (Index, int, int, error) {
+	index := Index{Commands: []CommandEntry{}}
+	pattern, err := filenamePattern(s.Options.FilenameTemplate)
+	if err != nil {
+		return index, 0, 0, err
+	}
+	entries, err := os.ReadDir(s.BaseDir)
+	if err != nil {
+		return index, 0, 0, err
+	}
+
+	byHash := make(map[string]*CommandEntry)
+	recovered, skipped := 0, 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || name == "index.json" || name == indexLockFile || strings.HasPrefix(name, corruptIndexPrefix) {
+			continue
+		}
+		rec, cmdHash, ok := s.recoverRecord(pattern, entry)
+		if !ok {
+			skipped++
+			continue
+		}
+		if byHash[cmdHash] == nil {
+			byHash[cmdHash] = &CommandEntry{CommandHash: cmdHash}
+		}
+		byHash[cmdHash].Executions = append(byHash[cmdHash].Executions, rec)
+		recovered++
+	}
+
+	for _, entry := range byHash {
+		sort.SliceStable(entry.Executions, func(i, j int) bool {
+			return entry.Executions[i].Timestamp.Before(entry.Executions[j].Timestamp)
+		})
+		index.Commands = append(index.Commands, *entry)
+	}
+	sort.Slice(index.Commands, func(i, j int) bool {
+		return index.Commands[i].CommandHash < index.Commands[j].CommandHash
+	})
+	return index, recovered, skipped, nil
+}
+
+// recoverRecord parses a response file name into an execution record and
+// the command hash it belongs to. Files without a version or hash in their
+// name can't be attributed and are reported as not ok.
+func (s *Store) recoverRecord(pattern *regexp.Regexp, entry os.DirEntry) (ExecutionRecord, string, bool) {
+	m := pattern.FindStringSubmatch(entry.Name())
+	if m == nil {
+		return ExecutionRecord{}, "", false
+	}
+	fields := make(map[string]string)
+	for i, name := range pattern.SubexpNames() {
+		if name != "" && fields[name] == "" {
+			fields[name] = m[i]
+		}
+	}
+	cmdHash := fields["hash"]
+	if cmdHash == "" {
+		cmdHash = fields["hash8"]
+	}
+	if cmdHash == "" || fields["version"] == "" {
+		return ExecutionRecord{}, "", false
+	}
+
+	rec := ExecutionRecord{
+		Version:      fields["version"],
+		TestCase:     fields["testcase"],
+		ResponseFile: entry.Name(),
+		Status:       "success",
+	}
+	if ts, err := time.ParseInLocation("20060102T150405", fields["ts"], time.Local); err == nil {
+		rec.Timestamp = ts
+	} else if info, err := entry.Info(); err == nil {
+		rec.Timestamp = info.ModTime()
+	}
+	if content, err := ReadResponse(filepath.Join(s.BaseDir, entry.Name())); err == nil {
+		rec.ContentHash = ContentHash(content)
+	}
+	return rec, cmdHash, true
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// Redacted replaces secret values in stored commands
+const Redacted = "REDACTED"
+
+// sensitiveName matches header and query parameter names that carry secrets
+var sensitiveName = regexp.MustCompile(`(?i)^(authorization|proxy-authorization|cookie|set-cookie|password|passwd|pwd|api[-_]?key|x-api-key|.*token.*|.*secret.*|.*signature.*)$`)
+
+// safeShellWord matches arguments that need no quoting when re-joined
+var safeShellWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// RedactCommand masks secrets in a resolved command: sensitive headers,
+// credentials passed with -u/--user, cookies and sensitive query parameters.
+// Commands that can't be parsed are returned with only URLs redacted.
+func RedactCommand(command string) string {
+	args, err := shellwords.Parse(command)
+	if err != nil {
+		return redactURLs(command)
+	}
+	return RedactArgs(args)
+}
+
+// RedactArgs is RedactCommand for a command already split into arguments. It
+// returns the command line with arguments shell-quoted where needed.
+func RedactArgs(args []string) string {
+	args = append([]string(nil), args...)
+	for i, arg := range args {
+		prev := ""
+		if i > 0 {
+			prev = args[i-1]
+		}
+		switch prev {
+		case "-H", "--header", "--proxy-header":
+			args[i] = redactHeader(arg)
+			continue
+		case "-u", "--user", "-U", "--proxy-user":
+			if user, _, ok := strings.Cut(arg, ":"); ok {
+				args[i] = user + ":" + Redacted
+			} else {
+				args[i] = Redacted
+			}
+			continue
+		case "-b", "--cookie", "--oauth2-bearer":
+			args[i] = Redacted
+			continue
+		}
+		if strings.Contains(arg, "://") {
+			args[i] = RedactURL(arg)
+		}
+	}
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// redactHeader masks the value of a "Name: value" header with a sensitive name
+func redactHeader(header string) string {
+	name, _, ok := strings.Cut(header, ":")
+	if !ok || !sensitiveName.MatchString(strings.TrimSpace(name)) {
+		return header
+	}
+	return name + ": " + Redacted
+}
+
+// RedactHeaders returns a copy of response headers with sensitive values
+// (e.g. Set-Cookie) masked
+func RedactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if sensitiveName.MatchString(name) {
+			value = Redacted
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// RedactURL masks sensitive query parameters and userinfo passwords in a URL
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	changed := false
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), Redacted)
+		changed = true
+	}
+	query := u.Query()
+	for name := range query {
+		if sensitiveName.MatchString(name) {
+			query.Set(name, Redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// RedactQuery returns a copy of parsed query parameters with the values of
+// sensitive ones (e.g. api_key, token) masked
+func RedactQuery(query map[string][]string) map[string][]string {
+	if query == nil {
+		return nil
+	}
+	redacted := make(map[string][]string, len(query))
+	for name, values := range query {
+		if sensitiveName.MatchString(name) {
+			values = []string{Redacted}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// redactURLs redacts every URL-looking word in an unparsable command
+func redactURLs(command string) string {
+	words := strings.Fields(command)
+	for i, w := range words {
+		if strings.Contains(w, "://") {
+			words[i] = RedactURL(w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// shellQuote single-quotes an argument when it contains shell metacharacters
+func shellQuote(arg string) string {
+	if safeShellWord.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
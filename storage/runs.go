@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"api_diff_checker/clock"
+)
+
+// RunsDir is the subdirectory of the store that holds saved run results
+const RunsDir = "runs"
+
+// runIDFormat names saved runs after their UTC save time, so IDs sort chronologically
+const runIDFormat = "20060102T150405.000Z"
+
+var validRunID = regexp.MustCompile(`^[0-9A-Za-z.\-]+$`)
+
+// RunInfo describes a saved run
+type RunInfo struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Size    int64     `json:"size"`
+}
+
+// SaveRun writes a run result as JSON under <BaseDir>/runs and returns its
+// timestamp-based ID. The result is taken as interface{} because the core
+// package (which defines RunResult) depends on storage.
+func (s *Store) SaveRun(result interface{}) (string, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.BaseDir, RunsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	// Runs saved within the same millisecond get a numeric suffix
+	base := clock.Or(s.Options.Clock).Now().UTC().Format(runIDFormat)
+	id := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(dir, id+".json")); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, id+".json"), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write run: %w", err)
+	}
+	return id, nil
+}
+
+// LoadRun decodes the saved run with the given ID into result
+func (s *Store) LoadRun(id string, result interface{}) error {
+	if !validRunID.MatchString(id) {
+		return fmt.Errorf("invalid run ID: %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(s.BaseDir, RunsDir, id+".json"))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode run %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListRuns returns the saved runs, newest first
+func (s *Store) ListRuns() ([]RunInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(s.BaseDir, RunsDir))
+	if os.IsNotExist(err) {
+		return []RunInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	runs := []RunInfo{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		id := strings.TrimSuffix(name, ".json")
+		created := info.ModTime()
+		if len(id) >= len(runIDFormat) {
+			if t, err := time.Parse(runIDFormat, id[:len(runIDFormat)]); err == nil {
+				created = t
+			}
+		}
+		runs = append(runs, RunInfo{ID: id, Created: created, Size: info.Size()})
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].Created.Equal(runs[j].Created) {
+			return runs[i].Created.After(runs[j].Created)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+	return runs, nil
+}
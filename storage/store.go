@@ -9,15 +9,51 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+
+	"api_diff_checker/clock"
+	"api_diff_checker/logger"
 )
 
 // Store handles saving responses and indexing
 type Store struct {
 	BaseDir string
+	Options StoreOptions
 	mu      sync.Mutex
 	Index   Index
+
+	// FormatOnSave re-indents XML and HTML responses before storing them,
+	// as JSON always is, so they diff line by line. Responses that don't
+	// parse are stored as received. Enabled by NewStore.
+	FormatOnSave bool
+}
+
+// StoreOptions configures how responses are written
+type StoreOptions struct {
+	// ContentAddressed names response files after the command and content
+	// hash (<cmdhash8>_<contenthash8>.json) instead of a timestamp, so
+	// identical responses share one file and re-runs don't add duplicates
+	ContentAddressed bool
+
+	// Compress gzips response files (<name>.json.gz); ReadResponse reads
+	// both compressed and plain files, so existing stores keep working
+	Compress bool
+
+	// FilenameTemplate names response files (without extension), e.g.
+	// "{testcase}_{version}_{hash8}_{ts}". Empty uses DefaultFilenameTemplate.
+	// Ignored when ContentAddressed is set.
+	FilenameTemplate string
+
+	// Clock timestamps executions, response file names and saved runs
+	// (nil = the system clock). A fixed clock makes them reproducible.
+	Clock clock.Clock
+
+	// Logger receives storage warnings such as an index recovery
+	// (nil = printed to stdout)
+	Logger *logger.Logger
 }
 
 type Index struct {
@@ -32,18 +68,77 @@ type CommandEntry struct {
 
 type ExecutionRecord struct {
 	Version      string    `json:"version"`
+	TestCase     string    `json:"test_case,omitempty"`
 	Timestamp    time.Time `json:"timestamp"`
 	ResponseFile string    `json:"response_file"`
-	Status       string    `json:"status"` // "success", "error"
+	Status       string    `json:"status"` // "success", "error", "partial"
 	Error        string    `json:"error,omitempty"`
+
+	// ResolvedCommand is the command as executed (placeholders substituted),
+	// with secrets redacted
+	ResolvedCommand string `json:"resolved_command,omitempty"`
+
+	// StatusCode is the HTTP status of the response (0 if it was not captured)
+	StatusCode int `json:"status_code,omitempty"`
+
+	// ContentType is the response's Content-Type header, if it was captured
+	ContentType string `json:"content_type,omitempty"`
+
+	// Headers are the captured response headers, with secrets redacted
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// ContentHash is the SHA-256 of the stored (uncompressed) response.
+	// Executions with identical content share one response file.
+	ContentHash string `json:"content_hash,omitempty"`
+
+	// Truncated is set when the response exceeded max_response_bytes and
+	// only its beginning was stored
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ResponseMeta carries optional details about an execution to record in the index
+type ResponseMeta struct {
+	// ResolvedCommand is the executed command; it is redacted before storing
+	ResolvedCommand string
+
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+
+	// ContentType is the response's Content-Type header
+	ContentType string
+
+	// TestCase is the name of the test case the execution belongs to
+	TestCase string
+
+	// Headers are the response headers, if captured; they are redacted before storing
+	Headers map[string]string
+
+	// Truncated marks a response cut at the executor's size limit
+	Truncated bool
+
+	// Partial marks a response cut short by a timeout. It is stored with
+	// status "partial", so it is never picked as a version's latest response.
+	Partial bool
 }
 
+// MaxPrettyPrintBytes is the largest response that is re-indented before
+// being stored; bigger ones are written as received, avoiding another full
+// in-memory copy
+const MaxPrettyPrintBytes = 16 << 20
+
 func NewStore(baseDir string) *Store {
+	return NewStoreWithOptions(baseDir, StoreOptions{})
+}
+
+// NewStoreWithOptions creates a store with the given options, loading any existing index
+func NewStoreWithOptions(baseDir string, opts StoreOptions) *Store {
 	s := &Store{
 		BaseDir: baseDir,
+		Options: opts,
 		Index: Index{
 			Commands: []CommandEntry{},
 		},
+		FormatOnSave: true,
 	}
 
 	// Load existing index if present
@@ -55,11 +150,126 @@ func NewStore(baseDir string) *Store {
 	return s
 }
 
-// LoadIndex loads the index from disk
+// CheckWritable creates dir if needed and verifies files can be created in
+// it, so a bad output directory fails at startup rather than mid-run
+func CheckWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create %s: %w", dir, err)
+	}
+	return ProbeWritable(dir)
+}
+
+// ProbeWritable verifies files can be created in dir without creating dir
+// itself, so a health check leaves a missing directory missing. The probe
+// file is removed again.
+func ProbeWritable(dir string) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s does not exist", dir)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	f, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// OpenStore loads an existing store, failing if its index is missing or invalid.
+// Unlike NewStore it never starts from an empty index, which makes it suitable
+// for read-only inspection of stored runs.
+func OpenStore(baseDir string) (*Store, error) {
+	indexPath := filepath.Join(baseDir, "index.json")
+	if _, err := os.Stat(indexPath); err != nil {
+		return nil, fmt.Errorf("no index found in %s: %w", baseDir, err)
+	}
+
+	s := &Store{BaseDir: baseDir}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadIndexLocked(false); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ResponseKey identifies a stored response by command and version
+type ResponseKey struct {
+	CommandHash string
+	Version     string
+}
+
+// LatestResponses returns the most recent successful execution (with a
+// response file) for every command hash and version in the index
+func (s *Store) LatestResponses() map[ResponseKey]ExecutionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := make(map[ResponseKey]ExecutionRecord)
+	for _, entry := range s.Index.Commands {
+		for _, rec := range entry.Executions {
+			if rec.Status != "success" || rec.ResponseFile == "" {
+				continue
+			}
+			key := ResponseKey{CommandHash: entry.CommandHash, Version: rec.Version}
+			if prev, ok := latest[key]; !ok || rec.Timestamp.After(prev.Timestamp) {
+				latest[key] = rec.clone()
+			}
+		}
+	}
+	return latest
+}
+
+// References returns every execution that stored its response in filename,
+// oldest first. In content-addressed stores one file can serve many runs.
+func (s *Store) References(filename string) []ExecutionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var refs []ExecutionRecord
+	for _, entry := range s.Index.Commands {
+		for _, rec := range entry.Executions {
+			if rec.ResponseFile == filename {
+				refs = append(refs, rec.clone())
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Timestamp.Before(refs[j].Timestamp) })
+	return refs
+}
+
+// CommandFor returns the raw command recorded for a command hash
+func (s *Store) CommandFor(commandHash string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.Index.Commands {
+		if entry.CommandHash == commandHash {
+			return entry.CommandRaw
+		}
+	}
+	return ""
+}
+
+// LoadIndex reads index.json from BaseDir. An index that can't be parsed
+// (e.g. truncated by a crash) is backed up and rebuilt from the response
+// files, and the number of recovered records is logged.
 func (s *Store) LoadIndex() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.loadIndexLocked(true)
+}
 
+// loadIndexLocked reads the index, rebuilding an unparsable one if rebuild
+// is set (must be called with mutex held)
+func (s *Store) loadIndexLocked(rebuild bool) error {
 	indexPath := filepath.Join(s.BaseDir, "index.json")
 	data, err := os.ReadFile(indexPath)
 	if err != nil {
@@ -70,13 +280,31 @@ func (s *Store) LoadIndex() error {
 		return fmt.Errorf("failed to read index: %w", err)
 	}
 
-	if err := json.Unmarshal(data, &s.Index); err != nil {
-		return fmt.Errorf("failed to parse index: %w", err)
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		if !rebuild {
+			return fmt.Errorf("failed to parse index: %w", err)
+		}
+		// Another process sharing BaseDir may be recovering it too
+		unlock, lockErr := lockIndex(s.BaseDir)
+		if lockErr != nil {
+			return fmt.Errorf("failed to parse index: %w", err)
+		}
+		defer unlock()
+		if data, readErr := os.ReadFile(indexPath); readErr != nil || json.Unmarshal(data, &index) != nil {
+			return s.recoverIndexLocked(err)
+		}
 	}
+	s.Index = index
 
 	return nil
 }
 
+// SanitizeFilename makes name safe to use as a file name (see sanitizeFilename)
+func SanitizeFilename(name string) string {
+	return sanitizeFilename(name)
+}
+
 // sanitizeFilename removes or replaces characters that are invalid in filenames
 func sanitizeFilename(name string) string {
 	// Replace problematic characters with underscores
@@ -106,16 +334,17 @@ func sanitizeFilename(name string) string {
 }
 
 func (s *Store) SaveResponse(command, version string, response []byte, execErr error) (string, error) {
+	return s.SaveResponseWithMeta(command, version, response, execErr, ResponseMeta{})
+}
+
+// SaveResponseWithMeta is SaveResponse that also records execution details
+func (s *Store) SaveResponseWithMeta(command, version string, response []byte, execErr error, meta ResponseMeta) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	cmdHash := hash(command)
-	timestamp := time.Now()
-	tsStr := timestamp.Format("20060102T150405")
-
-	// Sanitize version for filename
-	safeVer := sanitizeFilename(version)
-	filename := fmt.Sprintf("v%s_%s_%s.json", safeVer, cmdHash[:8], tsStr)
+	timestamp := clock.Or(s.Options.Clock).Now()
+	filename := renderFilename(s.Options.FilenameTemplate, meta.TestCase, version, cmdHash, timestamp) + ".json"
 	filePath := filepath.Join(s.BaseDir, filename)
 
 	// Ensure dir exists with proper error handling
@@ -123,25 +352,50 @@ func (s *Store) SaveResponse(command, version string, response []byte, execErr e
 		return "", fmt.Errorf("failed to create storage directory: %w", err)
 	}
 
-	execRecord := ExecutionRecord{
-		Version:   version,
-		Timestamp: timestamp,
-		Status:    "success",
+	// Other processes may share BaseDir: hold the index lock from picking
+	// the file name until the index is saved
+	unlock, err := s.lockIndexLocked()
+	if err != nil {
+		return "", err
 	}
+	defer unlock()
 
+	execRecord := newRecord(version, timestamp, meta)
 	if execErr != nil {
-		execRecord.Status = "error"
 		execRecord.Error = execErr.Error()
-	} else if response != nil {
-		// Pretty print JSON
+		if !meta.Partial {
+			execRecord.Status = "error"
+		}
+	}
+	if response != nil && (execErr == nil || meta.Partial) {
+		// Pretty print JSON (and XML/HTML with FormatOnSave), save raw if
+		// it doesn't parse or is too large
+		content := response
 		var prettyJSON bytes.Buffer
-		if err := json.Indent(&prettyJSON, response, "", "  "); err == nil {
-			if writeErr := os.WriteFile(filePath, prettyJSON.Bytes(), 0644); writeErr != nil {
-				return "", fmt.Errorf("failed to write response file: %w", writeErr)
+		if len(response) <= MaxPrettyPrintBytes && json.Indent(&prettyJSON, response, "", "  ") == nil {
+			content = prettyJSON.Bytes()
+		} else if s.FormatOnSave && len(response) <= MaxPrettyPrintBytes {
+			if formatted, ok := formatMarkup(response, meta.ContentType); ok {
+				content = formatted
+			}
+		}
+
+		contentHash := ContentHash(content)
+		execRecord.ContentHash = contentHash
+		var write bool
+		filename, write = s.placeResponseLocked(filename, cmdHash, contentHash)
+		filePath = filepath.Join(s.BaseDir, filename)
+
+		if write && s.Options.Compress {
+			compressed, err := gzipBytes(content)
+			if err != nil {
+				return "", fmt.Errorf("failed to compress response: %w", err)
 			}
-		} else {
-			// Save raw if not JSON
-			if writeErr := os.WriteFile(filePath, response, 0644); writeErr != nil {
+			content = compressed
+		}
+		if write {
+			// Like the index, responses appear under their name only once complete
+			if writeErr := writeFileAtomic(filePath, content, 0644); writeErr != nil {
 				return "", fmt.Errorf("failed to write response file: %w", writeErr)
 			}
 		}
@@ -157,11 +411,95 @@ func (s *Store) SaveResponse(command, version string, response []byte, execErr e
 	return filePath, nil
 }
 
+// newRecord returns a successful execution record for meta, with secrets redacted
+func newRecord(version string, timestamp time.Time, meta ResponseMeta) ExecutionRecord {
+	rec := ExecutionRecord{
+		Version:     version,
+		TestCase:    meta.TestCase,
+		Timestamp:   timestamp,
+		Status:      "success",
+		StatusCode:  meta.StatusCode,
+		ContentType: meta.ContentType,
+		Headers:     RedactHeaders(meta.Headers),
+		Truncated:   meta.Truncated,
+	}
+	if meta.Partial {
+		rec.Status = "partial"
+	}
+	if meta.ResolvedCommand != "" {
+		rec.ResolvedCommand = RedactCommand(meta.ResolvedCommand)
+	}
+	return rec
+}
+
+// placeResponseLocked picks the file name for a response with the given
+// content hash, given the name from the filename template. write is false
+// when an identical response is already stored under the returned name.
+// Must be called with mutex held.
+func (s *Store) placeResponseLocked(filename, cmdHash, contentHash string) (string, bool) {
+	if s.Options.ContentAddressed {
+		filename = fmt.Sprintf("%s_%s.json", cmdHash[:8], contentHash[:8])
+	}
+	if s.Options.Compress {
+		filename += CompressedExt
+	}
+	if s.Options.ContentAddressed {
+		// Identical content is already stored under this name
+		_, err := os.Stat(filepath.Join(s.BaseDir, filename))
+		return filename, err != nil
+	}
+	if existing := s.fileWithContentLocked(contentHash); existing != "" {
+		// Point at the identical file stored by an earlier execution
+		return existing, false
+	}
+	// Never overwrite a file from another execution with the same name
+	return uniqueFilename(s.BaseDir, filename), true
+}
+
+// uniqueFilename returns name, or name with a numeric suffix before its
+// extension if a file of that name already exists in dir
+func uniqueFilename(dir, name string) string {
+	if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+		return name
+	}
+	base, ext := name, ""
+	if i := strings.Index(name, ".json"); i >= 0 {
+		base, ext = name[:i], name[i:]
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s_%d%s", base, n, ext)
+		if _, err := os.Stat(filepath.Join(dir, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
+
+// fileWithContentLocked returns an existing response file recorded with the
+// given content hash, or "" if there is none (must be called with mutex held)
+func (s *Store) fileWithContentLocked(contentHash string) string {
+	for _, entry := range s.Index.Commands {
+		for i := len(entry.Executions) - 1; i >= 0; i-- {
+			rec := entry.Executions[i]
+			if rec.ContentHash != contentHash || rec.ResponseFile == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(s.BaseDir, rec.ResponseFile)); err == nil {
+				return rec.ResponseFile
+			}
+		}
+	}
+	return ""
+}
+
 func (s *Store) updateIndex(command, hash string, record ExecutionRecord) {
 	// Find command entry
 	found := false
 	for i, entry := range s.Index.Commands {
-		if entry.CommandHash == hash {
+		// Entries rebuilt from file names may only know a hash prefix
+		recovered := entry.CommandRaw == "" && entry.CommandHash != "" && strings.HasPrefix(hash, entry.CommandHash)
+		if entry.CommandHash == hash || recovered {
+			s.Index.Commands[i].CommandHash = hash
+			s.Index.Commands[i].CommandRaw = command
 			s.Index.Commands[i].Executions = append(s.Index.Commands[i].Executions, record)
 			found = true
 			break
@@ -183,8 +521,9 @@ func (s *Store) saveIndexLocked() error {
 		return fmt.Errorf("failed to marshal index: %w", err)
 	}
 
+	// Written atomically, so a crash never leaves a partial index
 	indexPath := filepath.Join(s.BaseDir, "index.json")
-	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+	if err := writeFileAtomic(indexPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write index: %w", err)
 	}
 
@@ -195,6 +534,11 @@ func (s *Store) saveIndexLocked() error {
 func (s *Store) SaveIndex() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	unlock, err := lockIndex(s.BaseDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return s.saveIndexLocked()
 }
 
@@ -212,17 +556,32 @@ func (s *Store) GetResponsePath(filename string) string {
 func (s *Store) CleanOldResponses(maxAge time.Duration) (int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	unlock, err := s.lockIndexLocked()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
 
 	cutoff := time.Now().Add(-maxAge)
 	cleaned := 0
 
+	// Files shared with a recent execution are still in use
+	inUse := make(map[string]bool)
+	for _, entry := range s.Index.Commands {
+		for _, rec := range entry.Executions {
+			if rec.ResponseFile != "" && !rec.Timestamp.Before(cutoff) {
+				inUse[rec.ResponseFile] = true
+			}
+		}
+	}
+
 	entries, err := os.ReadDir(s.BaseDir)
 	if err != nil {
 		return 0, fmt.Errorf("failed to read storage directory: %w", err)
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() || entry.Name() == "index.json" {
+		if entry.IsDir() || entry.Name() == "index.json" || entry.Name() == indexLockFile || inUse[entry.Name()] {
 			continue
 		}
 
@@ -241,3 +600,66 @@ func (s *Store) CleanOldResponses(maxAge time.Duration) (int, error) {
 
 	return cleaned, nil
 }
+
+// CleanByCount keeps the keepPerCommand most recent executions of every
+// command, removing older executions from the index and deleting their
+// response files. Files still referenced by a kept execution are preserved,
+// and files that are already gone are skipped. Returns the number of files
+// deleted.
+func (s *Store) CleanByCount(keepPerCommand int) (int, error) {
+	if keepPerCommand < 0 {
+		return 0, fmt.Errorf("keep count cannot be negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lockIndexLocked()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	kept := make(map[string]bool)
+	dropped := make(map[string]bool)
+	for i, entry := range s.Index.Commands {
+		execs := append([]ExecutionRecord(nil), entry.Executions...)
+		sort.SliceStable(execs, func(a, b int) bool { return execs[a].Timestamp.After(execs[b].Timestamp) })
+		if len(execs) <= keepPerCommand {
+			for _, rec := range execs {
+				kept[rec.ResponseFile] = true
+			}
+			continue
+		}
+		for _, rec := range execs[keepPerCommand:] {
+			if rec.ResponseFile != "" {
+				dropped[rec.ResponseFile] = true
+			}
+		}
+		execs = execs[:keepPerCommand]
+		for _, rec := range execs {
+			kept[rec.ResponseFile] = true
+		}
+
+		// Keep the index in chronological order
+		sort.SliceStable(execs, func(a, b int) bool { return execs[a].Timestamp.Before(execs[b].Timestamp) })
+		s.Index.Commands[i].Executions = execs
+	}
+
+	cleaned := 0
+	for file := range dropped {
+		if kept[file] {
+			continue
+		}
+		err := os.Remove(filepath.Join(s.BaseDir, file))
+		if err == nil {
+			cleaned++
+		} else if !os.IsNotExist(err) {
+			fmt.Printf("[WARN] Failed to remove %s: %v\n", file, err)
+		}
+	}
+
+	if err := s.saveIndexLocked(); err != nil {
+		return cleaned, err
+	}
+	return cleaned, nil
+}
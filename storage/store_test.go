@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"api_diff_checker/clock"
+)
+
+func TestFixedClockMakesSavesReproducible(t *testing.T) {
+	frozen := clock.Fixed(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	const command = "curl http://localhost/users"
+
+	var names []string
+	var records []ExecutionRecord
+	for i := 0; i < 2; i++ {
+		store := NewStoreWithOptions(t.TempDir(), StoreOptions{Clock: frozen})
+		path, err := store.SaveResponse(command, "v1", []byte(`{"id": 1}`), nil)
+		if err != nil {
+			t.Fatalf("SaveResponse: %v", err)
+		}
+		record, ok := store.LatestFor(CommandHash(command), "v1")
+		if !ok {
+			t.Fatal("saved execution not found")
+		}
+		names = append(names, filepath.Base(path))
+		records = append(records, record)
+	}
+
+	if names[0] != names[1] {
+		t.Errorf("response files %q and %q differ under a fixed clock", names[0], names[1])
+	}
+	for _, record := range records {
+		if !record.Timestamp.Equal(frozen.Now()) {
+			t.Errorf("Timestamp = %v, want %v", record.Timestamp, frozen.Now())
+		}
+	}
+}
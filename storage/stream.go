@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"api_diff_checker/clock"
+)
+
+// SaveResponseStream stores a response read from r without holding all of it
+// in memory. Responses up to MaxPrettyPrintBytes are buffered and stored
+// exactly as SaveResponseWithMeta stores them; larger ones are copied to disk
+// as received, hashing them on the way. At most maxBytes are kept (0 = no
+// limit) and truncated reports whether r had more. If reading r fails the
+// partial file is removed and nothing is recorded, so the caller can record
+// the failure instead; a reader that ends early on purpose can report it
+// with a Partial() bool method, and the response is then recorded as partial.
+func (s *Store) SaveResponseStream(command, version string, r io.Reader, maxBytes int64, meta ResponseMeta) (path string, truncated bool, err error) {
+	headLimit := int64(MaxPrettyPrintBytes)
+	if maxBytes > 0 && maxBytes < headLimit {
+		headLimit = maxBytes
+	}
+	head, err := io.ReadAll(io.LimitReader(r, headLimit+1))
+	if err != nil {
+		return "", false, err
+	}
+	if int64(len(head)) <= headLimit {
+		meta.Partial = meta.Partial || cutShort(r)
+		path, err := s.SaveResponseWithMeta(command, version, head, nil, meta)
+		return path, false, err
+	}
+	if headLimit == maxBytes {
+		meta.Truncated = true
+		path, err := s.SaveResponseWithMeta(command, version, head[:maxBytes], nil, meta)
+		return path, true, err
+	}
+	return s.spillResponse(command, version, head, r, maxBytes, meta)
+}
+
+// spillResponse writes head followed by the rest of r to a temporary file in
+// the store, then moves it into place and records it
+func (s *Store) spillResponse(command, version string, head []byte, r io.Reader, maxBytes int64, meta ResponseMeta) (string, bool, error) {
+	if err := os.MkdirAll(s.BaseDir, 0755); err != nil {
+		return "", false, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(s.BaseDir, ".partial-*")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create response file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed into place
+
+	truncated, contentHash, err := copyResponse(tmp, head, r, maxBytes, s.Options.Compress)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write response file: %w", closeErr)
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0644)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lockIndexLocked()
+	if err != nil {
+		return "", false, err
+	}
+	defer unlock()
+
+	cmdHash := hash(command)
+	timestamp := clock.Or(s.Options.Clock).Now()
+	meta.Truncated = truncated
+	meta.Partial = meta.Partial || cutShort(r)
+	execRecord := newRecord(version, timestamp, meta)
+	execRecord.ContentHash = contentHash
+
+	template := renderFilename(s.Options.FilenameTemplate, meta.TestCase, version, cmdHash, timestamp) + ".json"
+	filename, write := s.placeResponseLocked(template, cmdHash, contentHash)
+	filePath := filepath.Join(s.BaseDir, filename)
+	if write {
+		if err := os.Rename(tmpPath, filePath); err != nil {
+			return "", false, fmt.Errorf("failed to write response file: %w", err)
+		}
+	}
+	execRecord.ResponseFile = filename
+
+	s.updateIndex(command, cmdHash, execRecord)
+	if err := s.saveIndexLocked(); err != nil {
+		// Log error but don't fail the whole operation
+		fmt.Printf("[WARN] Failed to save index: %v\n", err)
+	}
+
+	return filePath, truncated, nil
+}
+
+// cutShort reports whether r says it ended before the full response
+func cutShort(r io.Reader) bool {
+	p, ok := r.(interface{ Partial() bool })
+	return ok && p.Partial()
+}
+
+// copyResponse writes head and then the rest of r to f (gzipped if
+// compress), stopping after maxBytes in total (0 = no limit). It returns
+// whether r had more data and the content hash of what was written.
+func copyResponse(f *os.File, head []byte, r io.Reader, maxBytes int64, compress bool) (bool, string, error) {
+	var out io.Writer = f
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(f)
+		out = zw
+	}
+	hasher := sha256.New()
+	w := io.MultiWriter(out, hasher)
+
+	if _, err := w.Write(head); err != nil {
+		return false, "", fmt.Errorf("failed to write response file: %w", err)
+	}
+	rest := r
+	if maxBytes > 0 {
+		rest = io.LimitReader(r, maxBytes-int64(len(head)))
+	}
+	if _, err := io.Copy(w, rest); err != nil {
+		return false, "", err
+	}
+
+	truncated := false
+	if maxBytes > 0 {
+		// Anything left past the limit means the response was cut
+		var probe [1]byte
+		n, err := io.ReadFull(r, probe[:])
+		truncated = n > 0
+		if err != nil && err != io.EOF {
+			return false, "", err
+		}
+	}
+
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return false, "", fmt.Errorf("failed to compress response: %w", err)
+		}
+	}
+	return truncated, hex.EncodeToString(hasher.Sum(nil)), nil
+}